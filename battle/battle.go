@@ -0,0 +1,153 @@
+package battle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// Battle represents a cluster of kills and losses happening in the same solar system within a short period of time
+type Battle struct {
+	SolarSystemID    int64
+	StartTime        time.Time
+	EndTime          time.Time
+	Entries          []models.ZKillboardTimelineEntry
+	MessageChannel   string
+	MessageTimestamp string
+	ReportCount      int
+
+	lastSeen time.Time
+	killIDs  map[int64]bool
+}
+
+// NewBattle creates a new battle in the given solar system, starting at the provided time
+func NewBattle(solarSystemID int64, start time.Time) *Battle {
+	b := &Battle{
+		SolarSystemID: solarSystemID,
+		StartTime:     start,
+		EndTime:       start,
+		Entries:       make([]models.ZKillboardTimelineEntry, 0),
+		killIDs:       make(map[int64]bool),
+	}
+
+	return b
+}
+
+// Add adds the given timeline entry to the battle, extending its duration if required. Entries already part of the battle are ignored
+func (b *Battle) Add(item models.ZKillboardTimelineEntry, killTime time.Time) {
+	if b.killIDs[item.Entry.KillID] {
+		return
+	}
+
+	b.killIDs[item.Entry.KillID] = true
+	b.Entries = append(b.Entries, item)
+	b.lastSeen = time.Now()
+
+	if killTime.Before(b.StartTime) {
+		b.StartTime = killTime
+	}
+	if killTime.After(b.EndTime) {
+		b.EndTime = killTime
+	}
+}
+
+// Contains checks whether the battle already contains the kill with the given ID
+func (b *Battle) Contains(killID int64) bool {
+	return b.killIDs[killID]
+}
+
+// Posted returns whether a summary of the battle has already been posted to Slack in a way that allows it to be updated
+func (b *Battle) Posted() bool {
+	return len(b.MessageChannel) > 0 && len(b.MessageTimestamp) > 0
+}
+
+// Duration returns the time passed between the first and last kill of the battle
+func (b *Battle) Duration() time.Duration {
+	return b.EndTime.Sub(b.StartTime)
+}
+
+// ISKDestroyed returns the total ISK value of all ships destroyed by the tracked corporation, valued using the given price source if available
+func (b *Battle) ISKDestroyed(prices models.PriceSource) float64 {
+	var value float64
+
+	for _, item := range b.Entries {
+		if item.Type == models.ZKillboardEntryTypeKill {
+			value += item.Entry.Value(prices)
+		}
+	}
+
+	return value
+}
+
+// ISKLost returns the total ISK value of all ships lost by the tracked corporation, valued using the given price source if available
+func (b *Battle) ISKLost(prices models.PriceSource) float64 {
+	var value float64
+
+	for _, item := range b.Entries {
+		if item.Type == models.ZKillboardEntryTypeLoss {
+			value += item.Entry.Value(prices)
+		}
+	}
+
+	return value
+}
+
+// ShipsDestroyed returns the number of ships destroyed by the tracked corporation
+func (b *Battle) ShipsDestroyed() int {
+	count := 0
+
+	for _, item := range b.Entries {
+		if item.Type == models.ZKillboardEntryTypeKill {
+			count++
+		}
+	}
+
+	return count
+}
+
+// ShipsLost returns the number of ships lost by the tracked corporation
+func (b *Battle) ShipsLost() int {
+	return len(b.Entries) - b.ShipsDestroyed()
+}
+
+// Participants returns the number of unique pilots involved on the side of the given corporation and on the hostile side
+func (b *Battle) Participants(corporationID int64) (int, int) {
+	friendly := make(map[int64]bool)
+	hostile := make(map[int64]bool)
+
+	for _, item := range b.Entries {
+		if item.Entry.Victim.CharacterID != 0 {
+			if item.Type == models.ZKillboardEntryTypeLoss {
+				friendly[item.Entry.Victim.CharacterID] = true
+			} else {
+				hostile[item.Entry.Victim.CharacterID] = true
+			}
+		}
+
+		for _, attacker := range item.Entry.Attackers {
+			if attacker.CharacterID == 0 {
+				continue
+			}
+
+			if attacker.CorporationID == corporationID {
+				friendly[attacker.CharacterID] = true
+			} else if item.Type == models.ZKillboardEntryTypeLoss {
+				hostile[attacker.CharacterID] = true
+			}
+		}
+	}
+
+	for characterID := range friendly {
+		delete(hostile, characterID)
+	}
+
+	return len(friendly), len(hostile)
+}
+
+// RelatedLink returns the link to the zKillboard page listing all kills related to the battle
+func (b *Battle) RelatedLink() string {
+	middle := b.StartTime.Add(b.Duration() / 2).UTC()
+
+	return fmt.Sprintf("https://zkillboard.com/related/%d/%s/", b.SolarSystemID, middle.Format("2006010215")+"00")
+}
@@ -0,0 +1,112 @@
+package battle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	testCorporationID = 98388312
+)
+
+// testBattle creates a battle containing two kills and one loss of the test corporation
+func testBattle() *Battle {
+	b := NewBattle(30000142, time.Date(2016, 8, 21, 18, 0, 0, 0, time.UTC))
+
+	kill := testEntry(models.ZKillboardEntryTypeKill, 1, 30000142, "2016-08-21 18:00:00")
+	kill.Entry.Victim.CharacterID = 1001
+	kill.Entry.Misc.TotalValue = 100
+	kill.Entry.Attackers = []models.ZKillboardAttacker{
+		{CharacterID: 2001, CorporationID: testCorporationID},
+		{CharacterID: 2002, CorporationID: testCorporationID},
+	}
+
+	secondKill := testEntry(models.ZKillboardEntryTypeKill, 2, 30000142, "2016-08-21 18:05:00")
+	secondKill.Entry.Victim.CharacterID = 1002
+	secondKill.Entry.Misc.TotalValue = 50
+	secondKill.Entry.Attackers = []models.ZKillboardAttacker{
+		{CharacterID: 2001, CorporationID: testCorporationID},
+	}
+
+	loss := testEntry(models.ZKillboardEntryTypeLoss, 3, 30000142, "2016-08-21 18:10:00")
+	loss.Entry.Victim.CharacterID = 2002
+	loss.Entry.Victim.CorporationID = testCorporationID
+	loss.Entry.Misc.TotalValue = 75
+	loss.Entry.Attackers = []models.ZKillboardAttacker{
+		{CharacterID: 1001, CorporationID: 98000001},
+		{CharacterID: 1003, CorporationID: 98000001},
+		{CharacterID: 0, CorporationID: 0},
+	}
+
+	for _, item := range []models.ZKillboardTimelineEntry{kill, secondKill, loss} {
+		killTime, _ := item.Entry.ParseKillTime()
+		b.Add(item, killTime)
+	}
+
+	return b
+}
+
+func TestBattleTotals(t *testing.T) {
+	b := testBattle()
+
+	if b.ShipsDestroyed() != 2 || b.ShipsLost() != 1 {
+		t.Errorf("Expected 2 ships destroyed and 1 ship lost, got %d and %d", b.ShipsDestroyed(), b.ShipsLost())
+	}
+
+	if b.ISKDestroyed(nil) != 150 || b.ISKLost(nil) != 75 {
+		t.Errorf("Expected 150 ISK destroyed and 75 ISK lost, got %.0f and %.0f", b.ISKDestroyed(nil), b.ISKLost(nil))
+	}
+
+	if b.Duration() != time.Minute*10 {
+		t.Errorf("Expected duration of 10 minutes, got %v", b.Duration())
+	}
+}
+
+func TestBattleParticipants(t *testing.T) {
+	friendly, hostile := testBattle().Participants(testCorporationID)
+
+	// Friendly: 2001 and 2002, hostile: victims 1001 and 1002 as well as attacker 1003
+	if friendly != 2 || hostile != 3 {
+		t.Errorf("Expected 2 friendly and 3 hostile pilots, got %d and %d", friendly, hostile)
+	}
+}
+
+func TestBattleAddIgnoresDuplicates(t *testing.T) {
+	b := testBattle()
+
+	item := b.Entries[0]
+	killTime, _ := item.Entry.ParseKillTime()
+	b.Add(item, killTime)
+
+	if len(b.Entries) != 3 {
+		t.Errorf("Expected duplicate entry to be ignored, got %d entries", len(b.Entries))
+	}
+	if !b.Contains(item.Entry.KillID) || b.Contains(4) {
+		t.Errorf("Expected battle to contain only added kill IDs")
+	}
+}
+
+func TestBattleRelatedLink(t *testing.T) {
+	link := testBattle().RelatedLink()
+
+	if link != "https://zkillboard.com/related/30000142/201608211800/" {
+		t.Errorf("Unexpected related link %q", link)
+	}
+}
+
+func TestBattlePosted(t *testing.T) {
+	b := testBattle()
+
+	if b.Posted() {
+		t.Errorf("Expected new battle not to be posted")
+	}
+
+	b.MessageChannel = "C123"
+	b.MessageTimestamp = "1471802400.000100"
+
+	if !b.Posted() {
+		t.Errorf("Expected battle with message channel and timestamp to be posted")
+	}
+}
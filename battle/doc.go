@@ -0,0 +1,2 @@
+// Package battle provides the clustering of kills and losses into battles, used by the application to aggregate large fights into a single report.
+package battle
@@ -0,0 +1,88 @@
+package battle
+
+import (
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	// DefaultMinimumKills represents the number of kills and losses required for a cluster to be treated as a battle if none was configured
+	DefaultMinimumKills = 5
+	// DefaultWindow represents the maximum time between two kills of the same battle if none was configured
+	DefaultWindow = time.Minute * 15
+)
+
+// Tracker groups kills and losses of a corporation into battles by solar system and time
+type Tracker struct {
+	// MinimumKills represents the number of kills and losses required for a cluster to be treated as a battle
+	MinimumKills int
+	// Window represents the maximum time between two kills of the same battle
+	Window time.Duration
+
+	battles map[int64][]*Battle
+}
+
+// NewTracker creates a new Tracker with the given minimum number of kills and time window, using the default values for non-positive arguments
+func NewTracker(minimumKills int, window time.Duration) *Tracker {
+	if minimumKills <= 0 {
+		minimumKills = DefaultMinimumKills
+	}
+	if window <= 0 {
+		window = DefaultWindow
+	}
+
+	t := &Tracker{
+		MinimumKills: minimumKills,
+		Window:       window,
+		battles:      make(map[int64][]*Battle),
+	}
+
+	return t
+}
+
+// Add assigns the given timeline entry to a battle in the same solar system, creating a new battle if none matches the kill time
+func (t *Tracker) Add(item models.ZKillboardTimelineEntry) (*Battle, error) {
+	killTime, err := item.Entry.ParseKillTime()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range t.battles[item.Entry.SolarSystemID] {
+		if b.Contains(item.Entry.KillID) || (!killTime.Before(b.StartTime.Add(-t.Window)) && !killTime.After(b.EndTime.Add(t.Window))) {
+			b.Add(item, killTime)
+			return b, nil
+		}
+	}
+
+	b := NewBattle(item.Entry.SolarSystemID, killTime)
+	b.Add(item, killTime)
+
+	t.battles[item.Entry.SolarSystemID] = append(t.battles[item.Entry.SolarSystemID], b)
+
+	return b, nil
+}
+
+// IsBattle checks whether the given cluster contains enough kills and losses to be treated as a battle
+func (t *Tracker) IsBattle(b *Battle) bool {
+	return len(b.Entries) >= t.MinimumKills
+}
+
+// Expire removes all battles which did not see any activity within the tracker's time window
+func (t *Tracker) Expire(now time.Time) {
+	for solarSystemID, battles := range t.battles {
+		active := make([]*Battle, 0, len(battles))
+
+		for _, b := range battles {
+			if now.Sub(b.EndTime) <= t.Window || now.Sub(b.lastSeen) <= t.Window {
+				active = append(active, b)
+			}
+		}
+
+		if len(active) > 0 {
+			t.battles[solarSystemID] = active
+		} else {
+			delete(t.battles, solarSystemID)
+		}
+	}
+}
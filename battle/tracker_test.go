@@ -0,0 +1,171 @@
+package battle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// testEntry creates a timeline entry of the given type with the given kill ID, solar system and kill time
+func testEntry(entryType models.ZKillboardEntryType, killID int64, solarSystemID int64, killTime string) models.ZKillboardTimelineEntry {
+	return models.ZKillboardTimelineEntry{
+		Type: entryType,
+		Entry: models.ZKillboardEntry{
+			KillID:        killID,
+			SolarSystemID: solarSystemID,
+			KillTime:      killTime,
+		},
+	}
+}
+
+func TestNewTrackerDefaults(t *testing.T) {
+	tracker := NewTracker(0, 0)
+
+	if tracker.MinimumKills != DefaultMinimumKills || tracker.Window != DefaultWindow {
+		t.Errorf("Expected default minimum kills %d and window %v, got %d and %v", DefaultMinimumKills, DefaultWindow, tracker.MinimumKills, tracker.Window)
+	}
+}
+
+func TestTrackerAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []models.ZKillboardTimelineEntry
+		battles int
+		sizes   []int
+	}{
+		{
+			name: "same system within window",
+			entries: []models.ZKillboardTimelineEntry{
+				testEntry(models.ZKillboardEntryTypeKill, 1, 30000142, "2016-08-21 18:00:00"),
+				testEntry(models.ZKillboardEntryTypeLoss, 2, 30000142, "2016-08-21 18:10:00"),
+				testEntry(models.ZKillboardEntryTypeKill, 3, 30000142, "2016-08-21 18:20:00"),
+			},
+			battles: 1,
+			sizes:   []int{3, 3, 3},
+		},
+		{
+			name: "different systems",
+			entries: []models.ZKillboardTimelineEntry{
+				testEntry(models.ZKillboardEntryTypeKill, 1, 30000142, "2016-08-21 18:00:00"),
+				testEntry(models.ZKillboardEntryTypeKill, 2, 30002187, "2016-08-21 18:01:00"),
+			},
+			battles: 2,
+			sizes:   []int{1, 1},
+		},
+		{
+			name: "same system outside window",
+			entries: []models.ZKillboardTimelineEntry{
+				testEntry(models.ZKillboardEntryTypeKill, 1, 30000142, "2016-08-21 18:00:00"),
+				testEntry(models.ZKillboardEntryTypeKill, 2, 30000142, "2016-08-21 18:16:00"),
+			},
+			battles: 2,
+			sizes:   []int{1, 1},
+		},
+		{
+			name: "entry before battle start",
+			entries: []models.ZKillboardTimelineEntry{
+				testEntry(models.ZKillboardEntryTypeKill, 2, 30000142, "2016-08-21 18:10:00"),
+				testEntry(models.ZKillboardEntryTypeLoss, 1, 30000142, "2016-08-21 18:00:00"),
+			},
+			battles: 1,
+			sizes:   []int{2, 2},
+		},
+		{
+			name: "duplicate entry",
+			entries: []models.ZKillboardTimelineEntry{
+				testEntry(models.ZKillboardEntryTypeKill, 1, 30000142, "2016-08-21 18:00:00"),
+				testEntry(models.ZKillboardEntryTypeKill, 1, 30000142, "2016-08-21 18:00:00"),
+			},
+			battles: 1,
+			sizes:   []int{1, 1},
+		},
+	}
+
+	for _, test := range tests {
+		tracker := NewTracker(5, time.Minute*15)
+		clusters := make([]*Battle, 0, len(test.entries))
+
+		for _, item := range test.entries {
+			b, err := tracker.Add(item)
+			if err != nil {
+				t.Fatalf("%s: failed to add entry #%d: %v", test.name, item.Entry.KillID, err)
+			}
+
+			clusters = append(clusters, b)
+		}
+
+		unique := make(map[*Battle]bool)
+		for _, b := range clusters {
+			unique[b] = true
+		}
+
+		if len(unique) != test.battles {
+			t.Errorf("%s: expected %d battles, got %d", test.name, test.battles, len(unique))
+		}
+
+		for i, b := range clusters {
+			if len(b.Entries) != test.sizes[i] {
+				t.Errorf("%s: expected battle of entry #%d to contain %d entries, got %d", test.name, test.entries[i].Entry.KillID, test.sizes[i], len(b.Entries))
+			}
+		}
+	}
+}
+
+func TestTrackerAddExtendsBattle(t *testing.T) {
+	tracker := NewTracker(5, time.Minute*15)
+
+	b, _ := tracker.Add(testEntry(models.ZKillboardEntryTypeKill, 1, 30000142, "2016-08-21 18:00:00"))
+	tracker.Add(testEntry(models.ZKillboardEntryTypeKill, 2, 30000142, "2016-08-21 18:14:00"))
+	extended, _ := tracker.Add(testEntry(models.ZKillboardEntryTypeKill, 3, 30000142, "2016-08-21 18:28:00"))
+
+	if extended != b {
+		t.Errorf("Expected entry within the window of the extended battle to join it")
+	}
+
+	if b.Duration() != time.Minute*28 {
+		t.Errorf("Expected battle duration of 28 minutes, got %v", b.Duration())
+	}
+}
+
+func TestTrackerAddInvalidKillTime(t *testing.T) {
+	tracker := NewTracker(5, time.Minute*15)
+
+	_, err := tracker.Add(testEntry(models.ZKillboardEntryTypeKill, 1, 30000142, "yesterday"))
+	if err == nil {
+		t.Errorf("Expected error for invalid kill time")
+	}
+}
+
+func TestTrackerIsBattle(t *testing.T) {
+	tracker := NewTracker(3, time.Minute*15)
+
+	var b *Battle
+	for i := int64(1); i <= 3; i++ {
+		b, _ = tracker.Add(testEntry(models.ZKillboardEntryTypeKill, i, 30000142, "2016-08-21 18:00:00"))
+
+		if tracker.IsBattle(b) != (i >= 3) {
+			t.Errorf("Expected IsBattle to return %v for %d entries", i >= 3, i)
+		}
+	}
+}
+
+func TestTrackerExpire(t *testing.T) {
+	tracker := NewTracker(5, time.Minute*15)
+
+	b, _ := tracker.Add(testEntry(models.ZKillboardEntryTypeKill, 1, 30000142, "2016-08-21 18:00:00"))
+
+	tracker.Expire(time.Now())
+
+	again, _ := tracker.Add(testEntry(models.ZKillboardEntryTypeKill, 2, 30000142, "2016-08-21 18:05:00"))
+	if again != b {
+		t.Errorf("Expected recently active battle to be kept")
+	}
+
+	tracker.Expire(time.Now().Add(time.Minute * 16))
+
+	expired, _ := tracker.Add(testEntry(models.ZKillboardEntryTypeKill, 3, 30000142, "2016-08-21 18:06:00"))
+	if expired == b {
+		t.Errorf("Expected inactive battle to be expired")
+	}
+}
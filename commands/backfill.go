@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/parser"
+)
+
+const (
+	// backfillDateFormat represents the format of dates accepted by the backfill command
+	backfillDateFormat = "2006-01-02"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "backfill",
+		Usage:       "<corporation ID> <from YYYY-MM-DD> [to YYYY-MM-DD]",
+		Description: "Imports the kills and losses of a tracked corporation within the given dates (default until now) into the history without posting them and advances its checkpoints",
+		Run:         runBackfill,
+	})
+}
+
+// runBackfill imports the killmail history of the requested corporation
+func runBackfill(ctx *Context, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("Missing corporation ID or start date")
+	}
+
+	corporationID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid corporation ID %q", args[0])
+	}
+
+	from, err := time.Parse(backfillDateFormat, args[1])
+	if err != nil {
+		return fmt.Errorf("Invalid start date %q", args[1])
+	}
+
+	to := time.Now().UTC()
+	if len(args) > 2 {
+		to, err = time.Parse(backfillDateFormat, args[2])
+		if err != nil {
+			return fmt.Errorf("Invalid end date %q", args[2])
+		}
+
+		// Include the whole end date
+		to = to.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(to) {
+		return fmt.Errorf("Start date %q is not before end date", args[1])
+	}
+
+	parse, err := parser.SetupParser(ctx.Config, ctx.Database, time.Minute*5)
+	if err != nil {
+		return err
+	}
+
+	for _, corporation := range parse.Corporations {
+		if corporation.EVECorporationID != corporationID {
+			continue
+		}
+
+		misc.Logger.Infof("Backfilling corporation #%d from %s to %s", corporationID, from.Format(backfillDateFormat), to.Format(backfillDateFormat))
+
+		kills, losses, err := parse.Backfill(corporation, from, to)
+		if err != nil {
+			return err
+		}
+
+		misc.Logger.Infof("Imported %d kills and %d losses, checkpoints advanced to kill #%d and loss #%d", kills, losses, corporation.LastKillID, corporation.LastLossID)
+
+		return nil
+	}
+
+	return fmt.Errorf("Corporation #%d is not tracked", corporationID)
+}
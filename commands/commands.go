@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/morpheusxaut/eveslackkills/database"
+	"github.com/morpheusxaut/eveslackkills/misc"
+)
+
+// Context stores all dependencies available to subcommands
+type Context struct {
+	// Config stores the current configuration values being used
+	Config *misc.Configuration
+	// Database stores the connection to the database backend
+	Database database.Connection
+}
+
+// Command represents a single subcommand of the application
+type Command struct {
+	// Name represents the name used to invoke the command
+	Name string
+	// Usage represents the arguments accepted by the command
+	Usage string
+	// Description represents a short explanation of the command
+	Description string
+	// Run executes the command with the given arguments, returning an error if the execution failed
+	Run func(ctx *Context, args []string) error
+}
+
+var (
+	registry = make([]*Command, 0)
+)
+
+// Register adds the given command to the list of available subcommands
+func Register(command *Command) {
+	registry = append(registry, command)
+}
+
+// Run looks up the subcommand named by the first argument and executes it with the remaining arguments, returning an error if the command is unknown or failed
+func Run(ctx *Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("No command given")
+	}
+
+	for _, command := range registry {
+		if strings.EqualFold(command.Name, args[0]) {
+			return command.Run(ctx, args[1:])
+		}
+	}
+
+	return fmt.Errorf("Unknown command %q", args[0])
+}
+
+// PrintUsage writes a list of all available subcommands to the given writer
+func PrintUsage(w io.Writer) {
+	fmt.Fprintf(w, "Commands:\n")
+
+	for _, command := range registry {
+		fmt.Fprintf(w, "  %s %s\n    \t%s\n", command.Name, command.Usage, command.Description)
+	}
+}
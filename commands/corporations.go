@@ -0,0 +1,334 @@
+package commands
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/morpheusxaut/eveslackkills/lookup/esi"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+var (
+	corporationSubcommands = []*subcommand{
+		{Name: "add", Usage: "<corporation ID> [name]", Run: runCorporationAdd},
+		{Name: "list", Run: runCorporationList},
+		{Name: "remove", Usage: "<corporation ID>", Run: runCorporationRemove},
+		{Name: "edit", Usage: "<corporation ID> <field> <value>", Run: runCorporationEdit},
+	}
+	ignoreSubcommands = []*subcommand{
+		{Name: "add", Usage: "<corporation ID> <solar system ID>", Run: runIgnoreAdd},
+		{Name: "remove", Usage: "<corporation ID> <solar system ID>", Run: runIgnoreRemove},
+	}
+	// shipGroupPresets maps names usable instead of item group IDs to the groups they represent
+	shipGroupPresets = map[string][]int64{
+		"capitals":      models.CapitalShipGroupIDs,
+		"supercapitals": models.SupercapitalShipGroupIDs,
+	}
+)
+
+func init() {
+	Register(&Command{
+		Name:        "corp",
+		Usage:       subcommandUsage(corporationSubcommands),
+		Description: "Manages the tracked corporations; editable fields are name, killcomment, losscomment, alertvalue, alertstructures, alertmention, alertgroup-add and alertgroup-remove (group ID, capitals or supercapitals)",
+		Run: func(ctx *Context, args []string) error {
+			return runSubcommand(ctx, corporationSubcommands, args)
+		},
+	})
+	Register(&Command{
+		Name:        "ignore",
+		Usage:       subcommandUsage(ignoreSubcommands),
+		Description: "Manages the solar systems ignored for a tracked corporation",
+		Run: func(ctx *Context, args []string) error {
+			return runSubcommand(ctx, ignoreSubcommands, args)
+		},
+	})
+	Register(&Command{
+		Name:        "set-comment",
+		Usage:       "<corporation ID> <kill|loss> <comment>",
+		Description: "Sets the kill or loss comment of a tracked corporation, supporting {victimshipname}, {victimname}, {victimcorpname}, {killershipname}, {killername}, {killercorpname}, {killid} and {killlink}",
+		Run:         runSetComment,
+	})
+}
+
+// runCorporationAdd starts tracking the given corporation, resolving its name via ESI if none was provided
+func runCorporationAdd(ctx *Context, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("Missing corporation ID")
+	}
+
+	eveCorporationID, err := parseID(args[0], "corporation ID")
+	if err != nil {
+		return err
+	}
+
+	_, err = ctx.Database.LoadCorporationByEVECorporationID(eveCorporationID)
+	if err == nil {
+		return fmt.Errorf("Corporation #%d is already tracked", eveCorporationID)
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	name := strings.Join(args[1:], " ")
+	if len(name) == 0 {
+		names, err := esi.SetupClient(ctx.Config).FetchNames([]int64{eveCorporationID})
+		if err != nil || len(names) == 0 {
+			misc.Logger.Warnf("Failed to resolve name of corporation #%d: [%v]", eveCorporationID, err)
+		} else {
+			name = names[0].Name
+		}
+	}
+
+	corporation := &models.Corporation{
+		EVECorporationID: eveCorporationID,
+		Name:             name,
+		KillComment:      models.DefaultKillComment,
+		LossComment:      models.DefaultLossComment,
+	}
+
+	corporation, err = ctx.Database.SaveCorporation(corporation)
+	if err != nil {
+		return err
+	}
+
+	misc.Logger.Infof("Added corporation #%d (%s), use the backfill command to import its history without posting it", corporation.EVECorporationID, corporation.Name)
+
+	return nil
+}
+
+// runCorporationList prints all tracked corporations along with their settings
+func runCorporationList(ctx *Context, args []string) error {
+	corporations, err := ctx.Database.LoadAllCorporations()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(w, "ID\tName\tLast kill\tLast loss\tAlert value\tAlert structures\tAlert groups\tIgnored systems\n")
+
+	for _, corporation := range corporations {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%.2f\t%t\t%s\t%s\n", corporation.EVECorporationID, corporation.Name, corporation.LastKillID, corporation.LastLossID, corporation.AlertValue, corporation.AlertStructures, formatIDs(corporation.AlertShipGroups), formatIDs(corporation.IgnoredSolarSystems))
+	}
+
+	return w.Flush()
+}
+
+// runCorporationRemove stops tracking the given corporation, removing all associated data
+func runCorporationRemove(ctx *Context, args []string) error {
+	corporation, err := loadCorporationArgument(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Database.DeleteCorporation(corporation.ID)
+	if err != nil {
+		return err
+	}
+
+	misc.Logger.Infof("Removed corporation #%d (%s)", corporation.EVECorporationID, corporation.Name)
+
+	return nil
+}
+
+// runCorporationEdit changes a single setting of the given corporation
+func runCorporationEdit(ctx *Context, args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("Missing corporation ID, field or value")
+	}
+
+	corporation, err := loadCorporationArgument(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	field := strings.ToLower(args[1])
+	value := strings.Join(args[2:], " ")
+
+	switch field {
+	case "name":
+		corporation.Name = value
+		break
+	case "killcomment":
+		corporation.KillComment = value
+		break
+	case "losscomment":
+		corporation.LossComment = value
+		break
+	case "alertvalue":
+		alertValue, err := strconv.ParseFloat(value, 64)
+		if err != nil || alertValue < 0 {
+			return fmt.Errorf("Invalid alert value %q", value)
+		}
+
+		corporation.AlertValue = alertValue
+		break
+	case "alertstructures":
+		alertStructures, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("Invalid boolean %q", value)
+		}
+
+		corporation.AlertStructures = alertStructures
+		break
+	case "alertmention":
+		corporation.AlertMention = value
+		break
+	case "alertgroup-add", "alertgroup-remove":
+		groupIDs, err := parseShipGroups(value)
+		if err != nil {
+			return err
+		}
+
+		for _, groupID := range groupIDs {
+			if field == "alertgroup-add" {
+				err = ctx.Database.AddAlertShipGroup(corporation.ID, groupID)
+			} else {
+				err = ctx.Database.RemoveAlertShipGroup(corporation.ID, groupID)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		misc.Logger.Infof("Updated alert ship groups of corporation #%d", corporation.EVECorporationID)
+
+		return nil
+	default:
+		return fmt.Errorf("Unknown field %q", args[1])
+	}
+
+	err = ctx.Database.UpdateCorporationSettings(corporation)
+	if err != nil {
+		return err
+	}
+
+	misc.Logger.Infof("Updated %s of corporation #%d", field, corporation.EVECorporationID)
+
+	return nil
+}
+
+// runIgnoreAdd adds a solar system to the ignore list of the given corporation
+func runIgnoreAdd(ctx *Context, args []string) error {
+	corporation, solarSystemID, err := parseIgnoreArguments(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Database.AddIgnoredSolarSystem(corporation.ID, solarSystemID)
+	if err != nil {
+		return err
+	}
+
+	misc.Logger.Infof("Ignoring solar system #%d for corporation #%d", solarSystemID, corporation.EVECorporationID)
+
+	return nil
+}
+
+// runIgnoreRemove removes a solar system from the ignore list of the given corporation
+func runIgnoreRemove(ctx *Context, args []string) error {
+	corporation, solarSystemID, err := parseIgnoreArguments(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Database.RemoveIgnoredSolarSystem(corporation.ID, solarSystemID)
+	if err != nil {
+		return err
+	}
+
+	misc.Logger.Infof("No longer ignoring solar system #%d for corporation #%d", solarSystemID, corporation.EVECorporationID)
+
+	return nil
+}
+
+// runSetComment sets the kill or loss comment of the given corporation
+func runSetComment(ctx *Context, args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("Missing corporation ID, comment type or comment")
+	}
+
+	switch strings.ToLower(args[1]) {
+	case "kill":
+		return runCorporationEdit(ctx, append([]string{args[0], "killcomment"}, args[2:]...))
+	case "loss":
+		return runCorporationEdit(ctx, append([]string{args[0], "losscomment"}, args[2:]...))
+	default:
+		return fmt.Errorf("Unknown comment type %q", args[1])
+	}
+}
+
+// loadCorporationArgument loads the tracked corporation with the EVE corporation ID given as first argument
+func loadCorporationArgument(ctx *Context, args []string) (*models.Corporation, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("Missing corporation ID")
+	}
+
+	eveCorporationID, err := parseID(args[0], "corporation ID")
+	if err != nil {
+		return nil, err
+	}
+
+	corporation, err := ctx.Database.LoadCorporationByEVECorporationID(eveCorporationID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("Corporation #%d is not tracked", eveCorporationID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return corporation, nil
+}
+
+// parseIgnoreArguments loads the corporation and parses the solar system ID given as arguments to the ignore command
+func parseIgnoreArguments(ctx *Context, args []string) (*models.Corporation, int64, error) {
+	if len(args) < 2 {
+		return nil, 0, fmt.Errorf("Missing corporation ID or solar system ID")
+	}
+
+	solarSystemID, err := parseID(args[1], "solar system ID")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	corporation, err := loadCorporationArgument(ctx, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return corporation, solarSystemID, nil
+}
+
+// parseShipGroups parses an item group ID or the name of a ship group preset
+func parseShipGroups(value string) ([]int64, error) {
+	preset, ok := shipGroupPresets[strings.ToLower(value)]
+	if ok {
+		return preset, nil
+	}
+
+	groupID, err := parseID(value, "item group ID")
+	if err != nil {
+		return nil, err
+	}
+
+	return []int64{groupID}, nil
+}
+
+// formatIDs formats the given IDs as a comma separated list
+func formatIDs(ids []int64) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+
+	formatted := make([]string, 0, len(ids))
+	for _, id := range ids {
+		formatted = append(formatted, strconv.FormatInt(id, 10))
+	}
+
+	return strings.Join(formatted, ",")
+}
@@ -0,0 +1,2 @@
+// Package commands provides the subcommands of the application, used to perform one-off tasks instead of continuously running the parser.
+package commands
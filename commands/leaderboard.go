@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/parser"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "leaderboard",
+		Usage:       "[days] [corporation ID]",
+		Description: "Posts pilot leaderboards for the last days (default 30) for all or the given corporation",
+		Run:         runLeaderboard,
+	})
+}
+
+// runLeaderboard posts pilot leaderboards for the requested period
+func runLeaderboard(ctx *Context, args []string) error {
+	days := 30
+	var corporationID int64
+
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("Invalid number of days %q", args[0])
+		}
+
+		days = parsed
+	}
+
+	if len(args) > 1 {
+		parsed, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid corporation ID %q", args[1])
+		}
+
+		corporationID = parsed
+	}
+
+	parse, err := parser.SetupParser(ctx.Config, ctx.Database, time.Minute*5)
+	if err != nil {
+		return err
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -days)
+	name := fmt.Sprintf("%d day leaderboard", days)
+
+	for _, corporation := range parse.Corporations {
+		if corporationID > 0 && corporation.EVECorporationID != corporationID {
+			continue
+		}
+
+		misc.Logger.Debugf("Posting %s for corporation #%d", name, corporation.EVECorporationID)
+
+		err = parse.SendLeaderboard(corporation, name, from, to)
+		if err != nil {
+			return err
+		}
+
+		// Wait in order to abide to Slack's message limit
+		time.Sleep(time.Second * 1)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/morpheusxaut/eveslackkills/lookup/sde"
+	"github.com/morpheusxaut/eveslackkills/misc"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "sde-import",
+		Usage:       "[directory or URL]",
+		Description: "Imports (or refreshes) the static data used for offline lookups from a CSV conversion of the SDE (default " + sde.DefaultSource + ")",
+		Run:         runSDEImport,
+	})
+}
+
+// runSDEImport imports the Static Data Export from the given source, replacing all previously imported data
+func runSDEImport(ctx *Context, args []string) error {
+	source := sde.DefaultSource
+	if len(args) > 0 {
+		source = args[0]
+	}
+
+	misc.Logger.Infof("Importing static data from %q", source)
+
+	data, err := sde.NewImporter(source).Import()
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Database.SaveStaticData(data)
+	if err != nil {
+		return err
+	}
+
+	misc.Logger.Infof("Imported %d item types, %d item groups, %d item categories, %d solar systems, %d constellations and %d regions", len(data.ItemTypes), len(data.ItemGroups), len(data.ItemCategories), len(data.SolarSystems), len(data.Constellations), len(data.Regions))
+
+	return nil
+}
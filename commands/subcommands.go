@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// subcommand represents a single action of a command grouping multiple related actions
+type subcommand struct {
+	// Name represents the name used to invoke the action
+	Name string
+	// Usage represents the arguments accepted by the action
+	Usage string
+	// Run executes the action with the given arguments, returning an error if the execution failed
+	Run func(ctx *Context, args []string) error
+}
+
+// runSubcommand looks up the action named by the first argument and executes it with the remaining arguments, returning an error if the action is unknown or failed
+func runSubcommand(ctx *Context, subcommands []*subcommand, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("No action given, expected %s", subcommandUsage(subcommands))
+	}
+
+	for _, sub := range subcommands {
+		if strings.EqualFold(sub.Name, args[0]) {
+			return sub.Run(ctx, args[1:])
+		}
+	}
+
+	return fmt.Errorf("Unknown action %q, expected %s", args[0], subcommandUsage(subcommands))
+}
+
+// subcommandUsage returns the usage of all given actions, separated by pipes
+func subcommandUsage(subcommands []*subcommand) string {
+	usages := make([]string, 0, len(subcommands))
+
+	for _, sub := range subcommands {
+		usage := sub.Name
+		if len(sub.Usage) > 0 {
+			usage = fmt.Sprintf("%s %s", sub.Name, sub.Usage)
+		}
+
+		usages = append(usages, usage)
+	}
+
+	return strings.Join(usages, " | ")
+}
+
+// parseID parses the given argument as an ID, returning an error mentioning the provided description if it is invalid
+func parseID(arg string, description string) (int64, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("Invalid %s %q", description, arg)
+	}
+
+	return id, nil
+}
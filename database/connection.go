@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/morpheusxaut/eveslackkills/database/mysql"
 	"github.com/morpheusxaut/eveslackkills/misc"
@@ -13,6 +14,9 @@ type Connection interface {
 	// Connect tries to establish a connection to the database backend, returning an error if the attempt failed
 	Connect() error
 
+	// Ping verifies the connection to the database backend is still alive, returning an error if the backend could not be reached
+	Ping() error
+
 	// RawQuery performs a raw database query and returns a map of interfaces containing the retrieve data. An error is returned if the query failed
 	RawQuery(query string, v ...interface{}) ([]map[string]interface{}, error)
 
@@ -22,11 +26,80 @@ type Connection interface {
 	// LoadCorporation retrieves the corporation with the given ID from the database, returning an error if the query failed
 	LoadCorporation(corporationID int64) (*models.Corporation, error)
 
+	// LoadCorporationByEVECorporationID retrieves the corporation tracking the given EVE corporation ID from the database, returning an error if the query failed
+	LoadCorporationByEVECorporationID(eveCorporationID int64) (*models.Corporation, error)
+
 	// LoadAllIgnoredSolarSystemsForCorporation retrieves all ignored solar systems associated with the given corporation from the database, returning an error if the query failed
 	LoadAllIgnoredSolarSystemsForCorporation(corporationID int64) ([]int64, error)
 
-	// SaveCorporation saves a corporation to the database, returning the updated model or an error if the query failed
+	// LoadAllAlertShipGroupsForCorporation retrieves all item group IDs triggering a loss alert for the given corporation from the database, returning an error if the query failed
+	LoadAllAlertShipGroupsForCorporation(corporationID int64) ([]int64, error)
+
+	// SaveCorporation saves a corporation including its name, comments and alert settings to the database, returning the updated model or an error if the query failed
 	SaveCorporation(corporation *models.Corporation) (*models.Corporation, error)
+
+	// UpdateCorporationSettings saves only the name, comments and alert settings of an existing corporation to the database, leaving its last kill and loss IDs untouched. An error is returned if the query failed
+	UpdateCorporationSettings(corporation *models.Corporation) error
+
+	// SaveCorporationCheckpoints saves only the last kill and loss IDs of an existing corporation to the database, leaving its remaining settings untouched. Messages are logged using the given logger. An error is returned if the query failed
+	SaveCorporationCheckpoints(log *misc.Log, corporation *models.Corporation) error
+
+	// DeleteCorporation removes the corporation with the given ID along with its ignored solar systems, alert ship groups and killmail history from the database, returning an error if the query failed
+	DeleteCorporation(corporationID int64) error
+
+	// AddIgnoredSolarSystem adds the given solar system to the ignore list of the corporation, returning an error if the query failed
+	AddIgnoredSolarSystem(corporationID int64, solarSystemID int64) error
+
+	// RemoveIgnoredSolarSystem removes the given solar system from the ignore list of the corporation, returning an error if the query failed
+	RemoveIgnoredSolarSystem(corporationID int64, solarSystemID int64) error
+
+	// AddAlertShipGroup adds the given item group to the groups triggering a loss alert for the corporation, returning an error if the query failed
+	AddAlertShipGroup(corporationID int64, groupID int64) error
+
+	// RemoveAlertShipGroup removes the given item group from the groups triggering a loss alert for the corporation, returning an error if the query failed
+	RemoveAlertShipGroup(corporationID int64, groupID int64) error
+
+	// LoadKillmails retrieves all stored kills and losses of the given corporation with a kill time within the provided period, returning an error if the query failed
+	LoadKillmails(corporationID int64, from time.Time, to time.Time) ([]*models.Killmail, error)
+
+	// LoadKillmail retrieves the stored kill or loss of the given corporation with the provided kill ID and type, returning an error if the query failed
+	LoadKillmail(corporationID int64, killID int64, killType models.ZKillboardEntryType) (*models.Killmail, error)
+
+	// MarkKillmailReviewed flags the stored kill or loss with the given ID as reviewed by the provided user, returning an error if the query failed
+	MarkKillmailReviewed(killmailID int64, reviewedBy string) error
+
+	// SaveKillmail saves a kill or loss to the killmail history, logging messages using the given logger and returning the updated model or an error if the query failed
+	SaveKillmail(log *misc.Log, killmail *models.Killmail) (*models.Killmail, error)
+
+	// LoadStaticItemType retrieves the imported item type with the given ID, returning an error if the query failed
+	LoadStaticItemType(typeID int64) (*models.SDEItemType, error)
+
+	// LoadStaticItemGroup retrieves the imported item group with the given ID including the IDs of all types belonging to it, returning an error if the query failed
+	LoadStaticItemGroup(groupID int64) (*models.SDEItemGroup, error)
+
+	// LoadStaticItemCategory retrieves the imported item category with the given ID including the IDs of all groups belonging to it, returning an error if the query failed
+	LoadStaticItemCategory(categoryID int64) (*models.SDEItemCategory, error)
+
+	// LoadStaticSolarSystem retrieves the imported solar system with the given ID, returning an error if the query failed
+	LoadStaticSolarSystem(solarSystemID int64) (*models.SDESolarSystem, error)
+
+	// LoadStaticConstellation retrieves the imported constellation with the given ID, returning an error if the query failed
+	LoadStaticConstellation(constellationID int64) (*models.SDEConstellation, error)
+
+	// LoadStaticRegion retrieves the imported region with the given ID, returning an error if the query failed
+	LoadStaticRegion(regionID int64) (*models.SDERegion, error)
+
+	// SaveStaticData replaces all imported static data with the given data, returning an error if the query failed
+	SaveStaticData(data *models.SDEData) error
+
+	// LoadAllLookupCacheEntries retrieves all persisted lookup results, returning an error if the query failed
+	LoadAllLookupCacheEntries() ([]*models.LookupCacheEntry, error)
+
+	// SaveLookupCacheEntry persists a lookup result, replacing previous results of the same kind and ID, returning an error if the query failed
+	SaveLookupCacheEntry(entry *models.LookupCacheEntry) error
+
+	// DeleteStaleLookupCacheEntries removes all persisted lookup results not matching the given version, returning an error if the query failed
+	DeleteStaleLookupCacheEntries(version string) error
 }
 
 // SetupDatabase parses the database type set in the configuration and returns an appropriate database implementation or an error if the type is unknown
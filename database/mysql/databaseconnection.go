@@ -1,7 +1,9 @@
 package mysql
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/morpheusxaut/eveslackkills/misc"
 	"github.com/morpheusxaut/eveslackkills/models"
@@ -31,6 +33,15 @@ func (c *DatabaseConnection) Connect() error {
 	return nil
 }
 
+// Ping verifies the connection to the MySQL database is still alive, returning an error if the database could not be reached
+func (c *DatabaseConnection) Ping() error {
+	if c.conn == nil {
+		return fmt.Errorf("Not connected to database")
+	}
+
+	return c.conn.Ping()
+}
+
 // RawQuery performs a raw MySQL query and returns a map of interfaces containing the retrieve data. An error is returned if the query failed
 func (c *DatabaseConnection) RawQuery(query string, v ...interface{}) ([]map[string]interface{}, error) {
 	rows, err := c.conn.Query(query, v...)
@@ -68,7 +79,7 @@ func (c *DatabaseConnection) RawQuery(query string, v ...interface{}) ([]map[str
 func (c *DatabaseConnection) LoadAllCorporations() ([]*models.Corporation, error) {
 	var corporations []*models.Corporation
 
-	err := c.conn.Select(&corporations, "SELECT id, evecorporationid, lastkillid, lastlossid, name, killcomment, losscomment FROM corporations")
+	err := c.conn.Select(&corporations, "SELECT id, evecorporationid, lastkillid, lastlossid, name, killcomment, losscomment, alertvalue, alertstructures, alertmention FROM corporations")
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +91,13 @@ func (c *DatabaseConnection) LoadAllCorporations() ([]*models.Corporation, error
 		}
 
 		corporation.IgnoredSolarSystems = ignoredSolarSystems
+
+		alertShipGroups, err := c.LoadAllAlertShipGroupsForCorporation(corporation.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		corporation.AlertShipGroups = alertShipGroups
 	}
 
 	return corporations, nil
@@ -89,7 +107,7 @@ func (c *DatabaseConnection) LoadAllCorporations() ([]*models.Corporation, error
 func (c *DatabaseConnection) LoadCorporation(corporationID int64) (*models.Corporation, error) {
 	corporation := &models.Corporation{}
 
-	err := c.conn.Get(corporation, "SELECT id, evecorporationid, lastkillid, lastlossid, name, killcomment, losscomment FROM corporations WHERE id=?", corporationID)
+	err := c.conn.Get(corporation, "SELECT id, evecorporationid, lastkillid, lastlossid, name, killcomment, losscomment, alertvalue, alertstructures, alertmention FROM corporations WHERE id=?", corporationID)
 	if err != nil {
 		return nil, err
 	}
@@ -101,9 +119,28 @@ func (c *DatabaseConnection) LoadCorporation(corporationID int64) (*models.Corpo
 
 	corporation.IgnoredSolarSystems = ignoredSolarSystems
 
+	alertShipGroups, err := c.LoadAllAlertShipGroupsForCorporation(corporation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	corporation.AlertShipGroups = alertShipGroups
+
 	return corporation, nil
 }
 
+// LoadCorporationByEVECorporationID retrieves the corporation tracking the given EVE corporation ID from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadCorporationByEVECorporationID(eveCorporationID int64) (*models.Corporation, error) {
+	var corporationID int64
+
+	err := c.conn.Get(&corporationID, "SELECT id FROM corporations WHERE evecorporationid=?", eveCorporationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.LoadCorporation(corporationID)
+}
+
 // LoadAllIgnoredSolarSystemsForCorporation retrieves all ignored solar systems associated with the given corporation from the MySQL database, returning an error if the query failed
 func (c *DatabaseConnection) LoadAllIgnoredSolarSystemsForCorporation(corporationID int64) ([]int64, error) {
 	var ignoredSolarSystems []int64
@@ -116,15 +153,27 @@ func (c *DatabaseConnection) LoadAllIgnoredSolarSystemsForCorporation(corporatio
 	return ignoredSolarSystems, nil
 }
 
-// SaveCorporation saves a corporation to the database, returning the updated model or an error if the query failed
+// LoadAllAlertShipGroupsForCorporation retrieves all item group IDs triggering a loss alert for the given corporation from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadAllAlertShipGroupsForCorporation(corporationID int64) ([]int64, error) {
+	var alertShipGroups []int64
+
+	err := c.conn.Select(&alertShipGroups, "SELECT groupid FROM alertshipgroups WHERE corporationid=?", corporationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return alertShipGroups, nil
+}
+
+// SaveCorporation saves a corporation including its name, comments and alert settings to the database, returning the updated model or an error if the query failed
 func (c *DatabaseConnection) SaveCorporation(corporation *models.Corporation) (*models.Corporation, error) {
 	if corporation.ID > 0 {
-		_, err := c.conn.Exec("UPDATE corporations SET evecorporationid=?, lastkillid=?, lastlossid=? WHERE id=?", corporation.EVECorporationID, corporation.LastKillID, corporation.LastLossID, corporation.ID)
+		_, err := c.conn.Exec("UPDATE corporations SET evecorporationid=?, lastkillid=?, lastlossid=?, name=?, killcomment=?, losscomment=?, alertvalue=?, alertstructures=?, alertmention=? WHERE id=?", corporation.EVECorporationID, corporation.LastKillID, corporation.LastLossID, corporation.Name, corporation.KillComment, corporation.LossComment, corporation.AlertValue, corporation.AlertStructures, corporation.AlertMention, corporation.ID)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		resp, err := c.conn.Exec("INSERT INTO corporations(evecorporationid, lastkillid, lastlossid) VALUES(?, ?, ?)", corporation.EVECorporationID, corporation.LastKillID, corporation.LastLossID)
+		resp, err := c.conn.Exec("INSERT INTO corporations(evecorporationid, lastkillid, lastlossid, name, killcomment, losscomment, alertvalue, alertstructures, alertmention) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)", corporation.EVECorporationID, corporation.LastKillID, corporation.LastLossID, corporation.Name, corporation.KillComment, corporation.LossComment, corporation.AlertValue, corporation.AlertStructures, corporation.AlertMention)
 		if err != nil {
 			return nil, err
 		}
@@ -139,3 +188,131 @@ func (c *DatabaseConnection) SaveCorporation(corporation *models.Corporation) (*
 
 	return corporation, nil
 }
+
+// UpdateCorporationSettings saves only the name, comments and alert settings of an existing corporation to the MySQL database, leaving its last kill and loss IDs untouched. An error is returned if the query failed
+func (c *DatabaseConnection) UpdateCorporationSettings(corporation *models.Corporation) error {
+	_, err := c.conn.Exec("UPDATE corporations SET name=?, killcomment=?, losscomment=?, alertvalue=?, alertstructures=?, alertmention=? WHERE id=?", corporation.Name, corporation.KillComment, corporation.LossComment, corporation.AlertValue, corporation.AlertStructures, corporation.AlertMention, corporation.ID)
+	return err
+}
+
+// SaveCorporationCheckpoints saves only the last kill and loss IDs of an existing corporation to the MySQL database, leaving its remaining settings untouched. Messages are logged using the given logger. An error is returned if the query failed
+func (c *DatabaseConnection) SaveCorporationCheckpoints(log *misc.Log, corporation *models.Corporation) error {
+	log.Tracef("Saving checkpoints (last kill #%d, last loss #%d)", corporation.LastKillID, corporation.LastLossID)
+
+	_, err := c.conn.Exec("UPDATE corporations SET lastkillid=GREATEST(lastkillid, ?), lastlossid=GREATEST(lastlossid, ?) WHERE id=?", corporation.LastKillID, corporation.LastLossID, corporation.ID)
+	return err
+}
+
+// DeleteCorporation removes the corporation with the given ID along with its ignored solar systems, alert ship groups and killmail history from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) DeleteCorporation(corporationID int64) error {
+	tx, err := c.conn.Beginx()
+	if err != nil {
+		return err
+	}
+
+	queries := []string{
+		"DELETE FROM ignoredsolarsystems WHERE corporationid=?",
+		"DELETE FROM alertshipgroups WHERE corporationid=?",
+		"DELETE FROM killmails WHERE corporationid=?",
+		"DELETE FROM corporations WHERE id=?",
+	}
+
+	for _, query := range queries {
+		_, err = tx.Exec(query, corporationID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AddIgnoredSolarSystem adds the given solar system to the ignore list of the corporation in the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) AddIgnoredSolarSystem(corporationID int64, solarSystemID int64) error {
+	_, err := c.conn.Exec("INSERT INTO ignoredsolarsystems(corporationid, solarsystemid) SELECT ?, ? FROM DUAL WHERE NOT EXISTS (SELECT id FROM ignoredsolarsystems WHERE corporationid=? AND solarsystemid=?)", corporationID, solarSystemID, corporationID, solarSystemID)
+	return err
+}
+
+// RemoveIgnoredSolarSystem removes the given solar system from the ignore list of the corporation in the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) RemoveIgnoredSolarSystem(corporationID int64, solarSystemID int64) error {
+	_, err := c.conn.Exec("DELETE FROM ignoredsolarsystems WHERE corporationid=? AND solarsystemid=?", corporationID, solarSystemID)
+	return err
+}
+
+// AddAlertShipGroup adds the given item group to the groups triggering a loss alert for the corporation in the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) AddAlertShipGroup(corporationID int64, groupID int64) error {
+	_, err := c.conn.Exec("INSERT INTO alertshipgroups(corporationid, groupid) SELECT ?, ? FROM DUAL WHERE NOT EXISTS (SELECT id FROM alertshipgroups WHERE corporationid=? AND groupid=?)", corporationID, groupID, corporationID, groupID)
+	return err
+}
+
+// RemoveAlertShipGroup removes the given item group from the groups triggering a loss alert for the corporation in the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) RemoveAlertShipGroup(corporationID int64, groupID int64) error {
+	_, err := c.conn.Exec("DELETE FROM alertshipgroups WHERE corporationid=? AND groupid=?", corporationID, groupID)
+	return err
+}
+
+// LoadKillmails retrieves all stored kills and losses of the given corporation with a kill time within the provided period from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadKillmails(corporationID int64, from time.Time, to time.Time) ([]*models.Killmail, error) {
+	var rows []*killmailRow
+
+	err := c.conn.Select(&rows, "SELECT id, corporationid, killid, type, killtime, solarsystemid, totalvalue, reviewed, reviewedby, data FROM killmails WHERE corporationid=? AND killtime>=? AND killtime<? ORDER BY killtime, killid", corporationID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	killmails := make([]*models.Killmail, 0, len(rows))
+
+	for _, row := range rows {
+		killmail, err := row.toKillmail()
+		if err != nil {
+			return nil, err
+		}
+
+		killmails = append(killmails, killmail)
+	}
+
+	return killmails, nil
+}
+
+// LoadKillmail retrieves the stored kill or loss of the given corporation with the provided kill ID and type from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadKillmail(corporationID int64, killID int64, killType models.ZKillboardEntryType) (*models.Killmail, error) {
+	row := &killmailRow{}
+
+	err := c.conn.Get(row, "SELECT id, corporationid, killid, type, killtime, solarsystemid, totalvalue, reviewed, reviewedby, data FROM killmails WHERE corporationid=? AND killid=? AND type=?", corporationID, killID, int(killType))
+	if err != nil {
+		return nil, err
+	}
+
+	return row.toKillmail()
+}
+
+// MarkKillmailReviewed flags the stored kill or loss with the given ID as reviewed by the provided user in the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) MarkKillmailReviewed(killmailID int64, reviewedBy string) error {
+	_, err := c.conn.Exec("UPDATE killmails SET reviewed=1, reviewedby=? WHERE id=?", reviewedBy, killmailID)
+	return err
+}
+
+// SaveKillmail saves a kill or loss to the killmail history in the MySQL database, logging messages using the given logger and returning the updated model or an error if the query failed
+func (c *DatabaseConnection) SaveKillmail(log *misc.Log, killmail *models.Killmail) (*models.Killmail, error) {
+	log.Tracef("Saving %s #%d to killmail history", killmail.Type, killmail.KillID)
+
+	data, err := json.Marshal(killmail.Entry)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.conn.Exec("INSERT INTO killmails(corporationid, killid, type, killtime, solarsystemid, totalvalue, data) VALUES(?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id), killtime=VALUES(killtime), solarsystemid=VALUES(solarsystemid), totalvalue=VALUES(totalvalue), data=VALUES(data)", killmail.CorporationID, killmail.KillID, killmail.Type, killmail.KillTime.UTC(), killmail.SolarSystemID, killmail.TotalValue, string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	lastInsertedID, err := resp.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	killmail.ID = lastInsertedID
+
+	return killmail, nil
+}
@@ -17,6 +17,9 @@ CREATE TABLE IF NOT EXISTS `corporations` (
   `name` varchar(64) NOT NULL,
   `killcomment` varchar(256) NOT NULL,
   `losscomment` varchar(256) NOT NULL,
+  `alertvalue` double NOT NULL DEFAULT '0',
+  `alertstructures` tinyint(1) NOT NULL DEFAULT '0',
+  `alertmention` varchar(64) NOT NULL DEFAULT '',
   PRIMARY KEY (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
@@ -33,6 +36,121 @@ CREATE TABLE IF NOT EXISTS `ignoredsolarsystems` (
   CONSTRAINT `fk_ignoredregions_corporation` FOREIGN KEY (`corporationid`) REFERENCES `corporations` (`id`) ON UPDATE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
+-- Data exporting was unselected.
+
+
+-- Dumping structure for table eveslackkills.alertshipgroups
+CREATE TABLE IF NOT EXISTS `alertshipgroups` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `corporationid` int(11) NOT NULL,
+  `groupid` int(11) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `fk_alertshipgroups_corporation` (`corporationid`),
+  CONSTRAINT `fk_alertshipgroups_corporation` FOREIGN KEY (`corporationid`) REFERENCES `corporations` (`id`) ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- Data exporting was unselected.
+
+
+-- Dumping structure for table eveslackkills.killmails
+CREATE TABLE IF NOT EXISTS `killmails` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `corporationid` int(11) NOT NULL,
+  `killid` bigint(20) NOT NULL,
+  `type` tinyint(4) NOT NULL,
+  `killtime` datetime NOT NULL,
+  `solarsystemid` int(11) NOT NULL,
+  `totalvalue` double NOT NULL,
+  `reviewed` tinyint(1) NOT NULL DEFAULT '0',
+  `reviewedby` varchar(64) NOT NULL DEFAULT '',
+  `data` mediumtext NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_killmails_corporation_kill_type` (`corporationid`,`killid`,`type`),
+  KEY `idx_killmails_corporation_killtime` (`corporationid`,`killtime`),
+  CONSTRAINT `fk_killmails_corporation` FOREIGN KEY (`corporationid`) REFERENCES `corporations` (`id`) ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- Data exporting was unselected.
+
+
+-- Dumping structure for table eveslackkills.sdeitemtypes
+CREATE TABLE IF NOT EXISTS `sdeitemtypes` (
+  `typeid` int(11) NOT NULL,
+  `groupid` int(11) NOT NULL,
+  `name` varchar(128) NOT NULL,
+  `volume` double NOT NULL,
+  PRIMARY KEY (`typeid`),
+  KEY `idx_sdeitemtypes_groupid` (`groupid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- Data exporting was unselected.
+
+
+-- Dumping structure for table eveslackkills.sdeitemgroups
+CREATE TABLE IF NOT EXISTS `sdeitemgroups` (
+  `groupid` int(11) NOT NULL,
+  `categoryid` int(11) NOT NULL,
+  `name` varchar(128) NOT NULL,
+  PRIMARY KEY (`groupid`),
+  KEY `idx_sdeitemgroups_categoryid` (`categoryid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- Data exporting was unselected.
+
+
+-- Dumping structure for table eveslackkills.sdeitemcategories
+CREATE TABLE IF NOT EXISTS `sdeitemcategories` (
+  `categoryid` int(11) NOT NULL,
+  `name` varchar(128) NOT NULL,
+  PRIMARY KEY (`categoryid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- Data exporting was unselected.
+
+
+-- Dumping structure for table eveslackkills.sdesolarsystems
+CREATE TABLE IF NOT EXISTS `sdesolarsystems` (
+  `solarsystemid` int(11) NOT NULL,
+  `constellationid` int(11) NOT NULL,
+  `regionid` int(11) NOT NULL,
+  `name` varchar(128) NOT NULL,
+  `security` double NOT NULL,
+  PRIMARY KEY (`solarsystemid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- Data exporting was unselected.
+
+
+-- Dumping structure for table eveslackkills.sdeconstellations
+CREATE TABLE IF NOT EXISTS `sdeconstellations` (
+  `constellationid` int(11) NOT NULL,
+  `regionid` int(11) NOT NULL,
+  `name` varchar(128) NOT NULL,
+  PRIMARY KEY (`constellationid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- Data exporting was unselected.
+
+
+-- Dumping structure for table eveslackkills.sderegions
+CREATE TABLE IF NOT EXISTS `sderegions` (
+  `regionid` int(11) NOT NULL,
+  `name` varchar(128) NOT NULL,
+  PRIMARY KEY (`regionid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- Data exporting was unselected.
+
+
+-- Dumping structure for table eveslackkills.lookupcache
+CREATE TABLE IF NOT EXISTS `lookupcache` (
+  `kind` varchar(32) NOT NULL,
+  `id` bigint(20) NOT NULL,
+  `version` varchar(64) NOT NULL,
+  `data` text NOT NULL,
+  PRIMARY KEY (`kind`,`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- Data exporting was unselected.
 /*!40101 SET SQL_MODE=IFNULL(@OLD_SQL_MODE, '') */;
 /*!40014 SET FOREIGN_KEY_CHECKS=IF(@OLD_FOREIGN_KEY_CHECKS IS NULL, 1, @OLD_FOREIGN_KEY_CHECKS) */;
@@ -0,0 +1,37 @@
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET NAMES utf8mb4 */;
+
+-- Upgrades the tables of an existing installation, run eveslackkills_create.sql first to create missing tables.
+-- Columns are only added if they do not exist yet, so the script can safely be run multiple times
+USE `eveslackkills`;
+
+
+-- Adding loss alert settings to table eveslackkills.corporations
+SET @stmt = (SELECT IF(COUNT(*) = 0, 'ALTER TABLE `corporations` ADD COLUMN `alertvalue` double NOT NULL DEFAULT ''0'' AFTER `losscomment`', 'DO 0') FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'corporations' AND COLUMN_NAME = 'alertvalue');
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @stmt = (SELECT IF(COUNT(*) = 0, 'ALTER TABLE `corporations` ADD COLUMN `alertstructures` tinyint(1) NOT NULL DEFAULT ''0'' AFTER `alertvalue`', 'DO 0') FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'corporations' AND COLUMN_NAME = 'alertstructures');
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @stmt = (SELECT IF(COUNT(*) = 0, 'ALTER TABLE `corporations` ADD COLUMN `alertmention` varchar(64) NOT NULL DEFAULT '''' AFTER `alertstructures`', 'DO 0') FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'corporations' AND COLUMN_NAME = 'alertmention');
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+
+-- Adding review state to table eveslackkills.killmails
+SET @stmt = (SELECT IF(COUNT(*) = 0, 'ALTER TABLE `killmails` ADD COLUMN `reviewed` tinyint(1) NOT NULL DEFAULT ''0'' AFTER `totalvalue`', 'DO 0') FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'killmails' AND COLUMN_NAME = 'reviewed');
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @stmt = (SELECT IF(COUNT(*) = 0, 'ALTER TABLE `killmails` ADD COLUMN `reviewedby` varchar(64) NOT NULL DEFAULT '''' AFTER `reviewed`', 'DO 0') FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'killmails' AND COLUMN_NAME = 'reviewedby');
+PREPARE stmt FROM @stmt;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// killmailRow represents a single row of the killmails table, storing the killboard entry as JSON encoded data
+type killmailRow struct {
+	ID            int64
+	CorporationID int64
+	KillID        int64
+	Type          int
+	KillTime      time.Time
+	SolarSystemID int64
+	TotalValue    float64
+	Reviewed      bool
+	ReviewedBy    string
+	Data          string
+}
+
+// toKillmail converts the row to a killmail model, decoding the stored killboard entry
+func (r *killmailRow) toKillmail() (*models.Killmail, error) {
+	killmail := &models.Killmail{
+		ID:            r.ID,
+		CorporationID: r.CorporationID,
+		KillID:        r.KillID,
+		Type:          models.ZKillboardEntryType(r.Type),
+		KillTime:      r.KillTime,
+		SolarSystemID: r.SolarSystemID,
+		TotalValue:    r.TotalValue,
+		Reviewed:      r.Reviewed,
+		ReviewedBy:    r.ReviewedBy,
+	}
+
+	err := json.Unmarshal([]byte(r.Data), &killmail.Entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return killmail, nil
+}
@@ -0,0 +1,35 @@
+package mysql
+
+import "github.com/morpheusxaut/eveslackkills/models"
+
+// LoadAllLookupCacheEntries retrieves all persisted lookup results from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadAllLookupCacheEntries() ([]*models.LookupCacheEntry, error) {
+	var entries []*models.LookupCacheEntry
+
+	err := c.conn.Select(&entries, "SELECT kind, id, version, data FROM lookupcache")
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// SaveLookupCacheEntry persists a lookup result to the MySQL database, replacing previous results of the same kind and ID, returning an error if the query failed
+func (c *DatabaseConnection) SaveLookupCacheEntry(entry *models.LookupCacheEntry) error {
+	_, err := c.conn.Exec("INSERT INTO lookupcache(kind, id, version, data) VALUES(?, ?, ?, ?) ON DUPLICATE KEY UPDATE version=VALUES(version), data=VALUES(data)", entry.Kind, entry.ID, entry.Version, entry.Data)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteStaleLookupCacheEntries removes all persisted lookup results not matching the given version from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) DeleteStaleLookupCacheEntries(version string) error {
+	_, err := c.conn.Exec("DELETE FROM lookupcache WHERE version<>?", version)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// LoadStaticItemType retrieves the imported item type with the given ID from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadStaticItemType(typeID int64) (*models.SDEItemType, error) {
+	itemType := &models.SDEItemType{}
+
+	err := c.conn.Get(itemType, "SELECT typeid, groupid, name, volume FROM sdeitemtypes WHERE typeid=?", typeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return itemType, nil
+}
+
+// LoadStaticItemGroup retrieves the imported item group with the given ID including the IDs of all types belonging to it from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadStaticItemGroup(groupID int64) (*models.SDEItemGroup, error) {
+	itemGroup := &models.SDEItemGroup{}
+
+	err := c.conn.Get(itemGroup, "SELECT groupid, categoryid, name FROM sdeitemgroups WHERE groupid=?", groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.conn.Select(&itemGroup.Types, "SELECT typeid FROM sdeitemtypes WHERE groupid=?", groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return itemGroup, nil
+}
+
+// LoadStaticItemCategory retrieves the imported item category with the given ID including the IDs of all groups belonging to it from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadStaticItemCategory(categoryID int64) (*models.SDEItemCategory, error) {
+	itemCategory := &models.SDEItemCategory{}
+
+	err := c.conn.Get(itemCategory, "SELECT categoryid, name FROM sdeitemcategories WHERE categoryid=?", categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.conn.Select(&itemCategory.Groups, "SELECT groupid FROM sdeitemgroups WHERE categoryid=?", categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return itemCategory, nil
+}
+
+// LoadStaticSolarSystem retrieves the imported solar system with the given ID from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadStaticSolarSystem(solarSystemID int64) (*models.SDESolarSystem, error) {
+	solarSystem := &models.SDESolarSystem{}
+
+	err := c.conn.Get(solarSystem, "SELECT solarsystemid, constellationid, regionid, name, security FROM sdesolarsystems WHERE solarsystemid=?", solarSystemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return solarSystem, nil
+}
+
+// LoadStaticConstellation retrieves the imported constellation with the given ID from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadStaticConstellation(constellationID int64) (*models.SDEConstellation, error) {
+	constellation := &models.SDEConstellation{}
+
+	err := c.conn.Get(constellation, "SELECT constellationid, regionid, name FROM sdeconstellations WHERE constellationid=?", constellationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return constellation, nil
+}
+
+// LoadStaticRegion retrieves the imported region with the given ID from the MySQL database, returning an error if the query failed
+func (c *DatabaseConnection) LoadStaticRegion(regionID int64) (*models.SDERegion, error) {
+	region := &models.SDERegion{}
+
+	err := c.conn.Get(region, "SELECT regionid, name FROM sderegions WHERE regionid=?", regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return region, nil
+}
+
+// SaveStaticData replaces all imported static data in the MySQL database with the given data within a single transaction, returning an error if the query failed
+func (c *DatabaseConnection) SaveStaticData(data *models.SDEData) error {
+	tx, err := c.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = saveStaticData(tx, data)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// saveStaticData deletes all previously imported static data and inserts the given data using the provided transaction
+func saveStaticData(tx *sql.Tx, data *models.SDEData) error {
+	for _, table := range []string{"sdeitemtypes", "sdeitemgroups", "sdeitemcategories", "sdesolarsystems", "sdeconstellations", "sderegions"} {
+		_, err := tx.Exec("DELETE FROM " + table)
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO sdeitemtypes(typeid, groupid, name, volume) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, itemType := range data.ItemTypes {
+		_, err = stmt.Exec(itemType.TypeID, itemType.GroupID, itemType.Name, itemType.Volume)
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt, err = tx.Prepare("INSERT INTO sdeitemgroups(groupid, categoryid, name) VALUES(?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, itemGroup := range data.ItemGroups {
+		_, err = stmt.Exec(itemGroup.GroupID, itemGroup.CategoryID, itemGroup.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt, err = tx.Prepare("INSERT INTO sdeitemcategories(categoryid, name) VALUES(?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, itemCategory := range data.ItemCategories {
+		_, err = stmt.Exec(itemCategory.CategoryID, itemCategory.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt, err = tx.Prepare("INSERT INTO sdesolarsystems(solarsystemid, constellationid, regionid, name, security) VALUES(?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, solarSystem := range data.SolarSystems {
+		_, err = stmt.Exec(solarSystem.SolarSystemID, solarSystem.ConstellationID, solarSystem.RegionID, solarSystem.Name, solarSystem.Security)
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt, err = tx.Prepare("INSERT INTO sdeconstellations(constellationid, regionid, name) VALUES(?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, constellation := range data.Constellations {
+		_, err = stmt.Exec(constellation.ConstellationID, constellation.RegionID, constellation.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt, err = tx.Prepare("INSERT INTO sderegions(regionid, name) VALUES(?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, region := range data.Regions {
+		_, err = stmt.Exec(region.RegionID, region.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
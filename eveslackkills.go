@@ -1,14 +1,17 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"os"
 	"runtime"
 	"time"
 
+	"github.com/morpheusxaut/eveslackkills/commands"
 	"github.com/morpheusxaut/eveslackkills/database"
 	"github.com/morpheusxaut/eveslackkills/misc"
 	"github.com/morpheusxaut/eveslackkills/parser"
+	"github.com/morpheusxaut/eveslackkills/server"
 )
 
 func main() {
@@ -20,7 +23,11 @@ func main() {
 		os.Exit(2)
 	}
 
-	misc.SetupLogger(config.DebugLevel)
+	err = misc.SetupLogger(config)
+	if err != nil {
+		log.Fatalf("Failed to set up logger: [%v]", err)
+		os.Exit(2)
+	}
 
 	db, err := database.SetupDatabase(config)
 	if err != nil {
@@ -34,11 +41,40 @@ func main() {
 		os.Exit(2)
 	}
 
+	if flag.NArg() > 0 {
+		err = commands.Run(&commands.Context{
+			Config:   config,
+			Database: db,
+		}, flag.Args())
+		if err != nil {
+			misc.Logger.Criticalf("Failed to run command: [%v]", err)
+			commands.PrintUsage(os.Stderr)
+			os.Exit(2)
+		}
+
+		return
+	}
+
 	parse, err := parser.SetupParser(config, db, time.Minute*5)
 	if err != nil {
 		misc.Logger.Criticalf("Failed to set up parser: [%v]", err)
 		os.Exit(2)
 	}
 
+	if len(config.AdminListenAddress) > 0 {
+		srv, err := server.SetupServer(config, db, parse)
+		if err != nil {
+			misc.Logger.Criticalf("Failed to set up admin server: [%v]", err)
+			os.Exit(2)
+		}
+
+		go func() {
+			err := srv.ListenAndServe()
+			if err != nil {
+				misc.Logger.Errorf("Admin server stopped: [%v]", err)
+			}
+		}()
+	}
+
 	parse.Start()
 }
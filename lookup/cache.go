@@ -0,0 +1,200 @@
+package lookup
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/morpheusxaut/eveslackkills/database"
+	"github.com/morpheusxaut/eveslackkills/metrics"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	// cacheFormatVersion represents the version of the persisted data format, increase to invalidate all persisted lookup results
+	cacheFormatVersion = 2
+
+	cacheKindItemType     = "itemtype"
+	cacheKindItemGroup    = "itemgroup"
+	cacheKindItemCategory = "itemcategory"
+	cacheKindLocationInfo = "locationinfo"
+)
+
+// PersistentCache wraps a lookup client, persisting all resolved results to the database so warm starts do not require any lookup requests. PersistentCache is safe for concurrent use if the wrapped client is
+type PersistentCache struct {
+	mutex    sync.RWMutex
+	client   Client
+	database database.Connection
+	version  string
+	entries  map[string]*models.LookupCacheEntry
+}
+
+// NewPersistentCache creates a new PersistentCache for the given client, loading previously persisted results from the database and deleting results of previous data formats
+func NewPersistentCache(client Client, db database.Connection) *PersistentCache {
+	c := &PersistentCache{
+		client:   client,
+		database: db,
+		version:  strconv.Itoa(cacheFormatVersion),
+		entries:  make(map[string]*models.LookupCacheEntry),
+	}
+
+	err := db.DeleteStaleLookupCacheEntries(c.version)
+	if err != nil {
+		misc.Logger.Warnf("Failed to delete stale lookup results: [%v]", err)
+	}
+
+	entries, err := db.LoadAllLookupCacheEntries()
+	if err != nil {
+		misc.Logger.Warnf("Failed to load persisted lookup results: [%v]", err)
+		return c
+	}
+
+	for _, entry := range entries {
+		if entry.Version != c.version {
+			continue
+		}
+
+		c.entries[cacheKey(entry.Kind, entry.ID)] = entry
+	}
+
+	misc.Logger.Debugf("Loaded %d persisted lookup results", len(c.entries))
+
+	return c
+}
+
+// RefreshServerVersion refreshes the server version of the wrapped client. Persisted results are kept across server versions as static universe data rarely changes
+func (c *PersistentCache) RefreshServerVersion(log *misc.Log) error {
+	return c.client.RefreshServerVersion(log)
+}
+
+// ServerVersion returns the server version of the wrapped client
+func (c *PersistentCache) ServerVersion() string {
+	return c.client.ServerVersion()
+}
+
+// FetchItemType retrieves the item type information for the given ID, using the persisted result if available
+func (c *PersistentCache) FetchItemType(log *misc.Log, typeID int64) (*models.CRESTItemType, error) {
+	var item *models.CRESTItemType
+
+	if c.load(log, cacheKindItemType, typeID, &item) {
+		return item, nil
+	}
+
+	item, err := c.client.FetchItemType(log, typeID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.save(log, cacheKindItemType, typeID, item)
+
+	return item, nil
+}
+
+// FetchItemGroup retrieves the item group information for the given ID, using the persisted result if available
+func (c *PersistentCache) FetchItemGroup(log *misc.Log, groupID int64) (*models.CRESTItemGroup, error) {
+	var group *models.CRESTItemGroup
+
+	if c.load(log, cacheKindItemGroup, groupID, &group) {
+		return group, nil
+	}
+
+	group, err := c.client.FetchItemGroup(log, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.save(log, cacheKindItemGroup, groupID, group)
+
+	return group, nil
+}
+
+// FetchItemCategory retrieves the item category information for the given ID, using the persisted result if available
+func (c *PersistentCache) FetchItemCategory(log *misc.Log, categoryID int64) (*models.CRESTItemCategory, error) {
+	var category *models.CRESTItemCategory
+
+	if c.load(log, cacheKindItemCategory, categoryID, &category) {
+		return category, nil
+	}
+
+	category, err := c.client.FetchItemCategory(log, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.save(log, cacheKindItemCategory, categoryID, category)
+
+	return category, nil
+}
+
+// FetchLocationInfo retrieves all available location info for the given solar system ID, using the persisted result if available
+func (c *PersistentCache) FetchLocationInfo(log *misc.Log, systemID int64) (*models.CRESTLocationInfo, error) {
+	var info *models.CRESTLocationInfo
+
+	if c.load(log, cacheKindLocationInfo, systemID, &info) {
+		return info, nil
+	}
+
+	info, err := c.client.FetchLocationInfo(log, systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.save(log, cacheKindLocationInfo, systemID, info)
+
+	return info, nil
+}
+
+// load decodes the persisted result of the given kind and ID into v, returning false if no result is available
+func (c *PersistentCache) load(log *misc.Log, kind string, id int64, v interface{}) bool {
+	c.mutex.RLock()
+	entry, ok := c.entries[cacheKey(kind, id)]
+	c.mutex.RUnlock()
+
+	if !ok {
+		metrics.LookupCacheMisses.Inc("persistent", kind)
+		return false
+	}
+
+	err := json.Unmarshal([]byte(entry.Data), v)
+	if err != nil {
+		log.With(misc.Fields{"kind": kind, "id": id}).Warnf("Failed to decode persisted %s #%d: [%v]", kind, id, err)
+		return false
+	}
+
+	log.With(misc.Fields{"kind": kind, "id": id}).Tracef("Found persisted %s #%d in cache", kind, id)
+	metrics.LookupCacheHits.Inc("persistent", kind)
+
+	return true
+}
+
+// save persists the given result of the provided kind and ID, logging a warning if the database query failed
+func (c *PersistentCache) save(log *misc.Log, kind string, id int64, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.With(misc.Fields{"kind": kind, "id": id}).Warnf("Failed to encode %s #%d for persisting: [%v]", kind, id, err)
+		return
+	}
+
+	c.mutex.Lock()
+	entry := &models.LookupCacheEntry{
+		Kind:    kind,
+		ID:      id,
+		Version: c.version,
+		Data:    string(data),
+	}
+
+	c.entries[cacheKey(kind, id)] = entry
+	c.mutex.Unlock()
+
+	err = c.database.SaveLookupCacheEntry(entry)
+	if err != nil {
+		log.With(misc.Fields{"kind": kind, "id": id}).Warnf("Failed to persist %s #%d: [%v]", kind, id, err)
+	}
+}
+
+// cacheKey returns the key used to store results of the given kind and ID in memory
+func cacheKey(kind string, id int64) string {
+	return fmt.Sprintf("%s/%d", kind, id)
+}
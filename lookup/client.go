@@ -0,0 +1,54 @@
+package lookup
+
+import (
+	"fmt"
+
+	"github.com/morpheusxaut/eveslackkills/database"
+	"github.com/morpheusxaut/eveslackkills/lookup/esi"
+	"github.com/morpheusxaut/eveslackkills/lookup/sde"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// Client provides an interface for retrieving static universe data used to display kills and losses.
+// Messages logged while retrieving data are written using the given logger, carrying the context fields of the caller
+type Client interface {
+	// RefreshServerVersion retrieves the current server version, which implementations may use for invalidating cached data
+	RefreshServerVersion(log *misc.Log) error
+
+	// ServerVersion returns the server version detected during the last refresh
+	ServerVersion() string
+
+	// FetchItemType retrieves the item type information for the given ID
+	FetchItemType(log *misc.Log, typeID int64) (*models.CRESTItemType, error)
+
+	// FetchItemGroup retrieves the item group information for the given ID, including all types belonging to the group
+	FetchItemGroup(log *misc.Log, groupID int64) (*models.CRESTItemGroup, error)
+
+	// FetchItemCategory retrieves the item category information for the given ID, including all groups belonging to the category
+	FetchItemCategory(log *misc.Log, categoryID int64) (*models.CRESTItemCategory, error)
+
+	// FetchLocationInfo retrieves all available location info for the given solar system ID
+	FetchLocationInfo(log *misc.Log, systemID int64) (*models.CRESTLocationInfo, error)
+}
+
+// SetupLookup parses the lookup type set in the configuration and returns an appropriate lookup implementation or an error if the type is unknown
+func SetupLookup(conf *misc.Configuration, db database.Connection) (Client, error) {
+	var client Client
+
+	switch Type(conf.LookupType) {
+	case TypeESI:
+		client = NewPersistentCache(esi.SetupClient(conf), db)
+		break
+	case TypeCREST:
+		client = NewPersistentCache(models.NewCRESTClient("https://public-crest.eveonline.com/"), db)
+		break
+	case TypeSDE:
+		client = sde.NewClient(db)
+		break
+	default:
+		return nil, fmt.Errorf("Unknown type #%d", conf.LookupType)
+	}
+
+	return NewCoalescingClient(client), nil
+}
@@ -0,0 +1,184 @@
+package lookup
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	// DefaultConcurrency represents the number of concurrent lookups used for batch resolution if none was configured
+	DefaultConcurrency = 4
+)
+
+// call represents an in-flight lookup shared by all callers requesting the same result simultaneously
+type call struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+}
+
+// CoalescingClient wraps a lookup client, merging simultaneous lookups of the same ID into a single request. CoalescingClient is safe for concurrent use if the wrapped client is
+type CoalescingClient struct {
+	client Client
+	mutex  sync.Mutex
+	calls  map[string]*call
+}
+
+// NewCoalescingClient creates a new CoalescingClient for the given client
+func NewCoalescingClient(client Client) *CoalescingClient {
+	c := &CoalescingClient{
+		client: client,
+		calls:  make(map[string]*call),
+	}
+
+	return c
+}
+
+// RefreshServerVersion refreshes the server version of the wrapped client, merging simultaneous refreshes
+func (c *CoalescingClient) RefreshServerVersion(log *misc.Log) error {
+	_, err := c.do(log, "serverversion", func() (interface{}, error) {
+		return nil, c.client.RefreshServerVersion(log)
+	})
+
+	return err
+}
+
+// ServerVersion returns the server version of the wrapped client
+func (c *CoalescingClient) ServerVersion() string {
+	return c.client.ServerVersion()
+}
+
+// FetchItemType retrieves the item type information for the given ID, merging simultaneous lookups
+func (c *CoalescingClient) FetchItemType(log *misc.Log, typeID int64) (*models.CRESTItemType, error) {
+	value, err := c.do(log, cacheKey(cacheKindItemType, typeID), func() (interface{}, error) {
+		return c.client.FetchItemType(log, typeID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*models.CRESTItemType), nil
+}
+
+// FetchItemGroup retrieves the item group information for the given ID, merging simultaneous lookups
+func (c *CoalescingClient) FetchItemGroup(log *misc.Log, groupID int64) (*models.CRESTItemGroup, error) {
+	value, err := c.do(log, cacheKey(cacheKindItemGroup, groupID), func() (interface{}, error) {
+		return c.client.FetchItemGroup(log, groupID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*models.CRESTItemGroup), nil
+}
+
+// FetchItemCategory retrieves the item category information for the given ID, merging simultaneous lookups
+func (c *CoalescingClient) FetchItemCategory(log *misc.Log, categoryID int64) (*models.CRESTItemCategory, error) {
+	value, err := c.do(log, cacheKey(cacheKindItemCategory, categoryID), func() (interface{}, error) {
+		return c.client.FetchItemCategory(log, categoryID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*models.CRESTItemCategory), nil
+}
+
+// FetchLocationInfo retrieves all available location info for the given solar system ID, merging simultaneous lookups
+func (c *CoalescingClient) FetchLocationInfo(log *misc.Log, systemID int64) (*models.CRESTLocationInfo, error) {
+	value, err := c.do(log, cacheKey(cacheKindLocationInfo, systemID), func() (interface{}, error) {
+		return c.client.FetchLocationInfo(log, systemID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*models.CRESTLocationInfo), nil
+}
+
+// do executes the given function, unless a call for the same key is already in flight, in which case its result is awaited and returned instead
+func (c *CoalescingClient) do(log *misc.Log, key string, fn func() (interface{}, error)) (interface{}, error) {
+	c.mutex.Lock()
+	if existing, ok := c.calls[key]; ok {
+		c.mutex.Unlock()
+
+		log.Tracef("Waiting for in-flight lookup %q", key)
+
+		existing.wg.Wait()
+
+		return existing.value, existing.err
+	}
+
+	current := &call{}
+	current.wg.Add(1)
+	c.calls[key] = current
+	c.mutex.Unlock()
+
+	defer current.wg.Done()
+	defer func() {
+		c.mutex.Lock()
+		delete(c.calls, key)
+		c.mutex.Unlock()
+	}()
+
+	// Waiting callers receive this error if fn panics before returning a result
+	current.err = fmt.Errorf("Lookup %q did not complete", key)
+	current.value, current.err = fn()
+
+	return current.value, current.err
+}
+
+// ResolveItemTypes retrieves the item type information for all given IDs using up to the given number of concurrent lookups, using the default for non-positive values.
+// All successfully resolved types are returned, along with the first error encountered
+func ResolveItemTypes(log *misc.Log, client Client, typeIDs []int64, concurrency int) (map[int64]*models.CRESTItemType, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	unique := make(map[int64]bool)
+	for _, typeID := range typeIDs {
+		if typeID > 0 {
+			unique[typeID] = true
+		}
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+
+	itemTypes := make(map[int64]*models.CRESTItemType, len(unique))
+	semaphore := make(chan struct{}, concurrency)
+
+	for typeID := range unique {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(typeID int64) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			itemType, err := client.FetchItemType(log, typeID)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("Failed to resolve item type #%d: %v", typeID, err)
+				}
+				return
+			}
+
+			itemTypes[typeID] = itemType
+		}(typeID)
+	}
+
+	wg.Wait()
+
+	return itemTypes, firstErr
+}
@@ -0,0 +1,232 @@
+package lookup
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// fakeClient resolves item types, counting requests and optionally blocking until released, failing or panicking
+type fakeClient struct {
+	mutex    sync.Mutex
+	requests map[int64]int
+	release  chan struct{}
+	fail     map[int64]bool
+	panics   bool
+}
+
+// newFakeClient creates a new fakeClient, blocking every request until release is closed if block is set
+func newFakeClient(block bool) *fakeClient {
+	c := &fakeClient{
+		requests: make(map[int64]int),
+		fail:     make(map[int64]bool),
+	}
+
+	if block {
+		c.release = make(chan struct{})
+	}
+
+	return c
+}
+
+// Requests returns the number of requests made for the given type ID
+func (c *fakeClient) Requests(typeID int64) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.requests[typeID]
+}
+
+func (c *fakeClient) RefreshServerVersion(log *misc.Log) error {
+	return nil
+}
+
+func (c *fakeClient) ServerVersion() string {
+	return "1"
+}
+
+func (c *fakeClient) FetchItemType(log *misc.Log, typeID int64) (*models.CRESTItemType, error) {
+	c.mutex.Lock()
+	c.requests[typeID]++
+	panics := c.panics
+	c.mutex.Unlock()
+
+	if c.release != nil {
+		<-c.release
+	}
+
+	if panics {
+		panic("lookup failed")
+	}
+
+	if c.fail[typeID] {
+		return nil, fmt.Errorf("Unknown type #%d", typeID)
+	}
+
+	return &models.CRESTItemType{Name: fmt.Sprintf("Type %d", typeID)}, nil
+}
+
+func (c *fakeClient) FetchItemGroup(log *misc.Log, groupID int64) (*models.CRESTItemGroup, error) {
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (c *fakeClient) FetchItemCategory(log *misc.Log, categoryID int64) (*models.CRESTItemCategory, error) {
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func (c *fakeClient) FetchLocationInfo(log *misc.Log, systemID int64) (*models.CRESTLocationInfo, error) {
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func TestCoalescingClientMergesLookups(t *testing.T) {
+	source := newFakeClient(true)
+	client := NewCoalescingClient(source)
+
+	var wg sync.WaitGroup
+	results := make([]*models.CRESTItemType, 10)
+
+	for i := range results {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			itemType, err := client.FetchItemType(misc.Logger, 587)
+			if err != nil {
+				t.Errorf("Failed to fetch item type: %v", err)
+				return
+			}
+
+			results[i] = itemType
+		}(i)
+	}
+
+	// Give all callers the chance to join the in-flight lookup before releasing it
+	time.Sleep(time.Millisecond * 100)
+	close(source.release)
+	wg.Wait()
+
+	if source.Requests(587) != 1 {
+		t.Errorf("Expected a single request, got %d", source.Requests(587))
+	}
+
+	for i, itemType := range results {
+		if itemType == nil || itemType.Name != "Type 587" {
+			t.Errorf("Caller %d: expected item type 587, got %v", i, itemType)
+		}
+	}
+}
+
+func TestCoalescingClientForgetsFinishedLookups(t *testing.T) {
+	source := newFakeClient(false)
+	source.fail[587] = true
+	client := NewCoalescingClient(source)
+
+	_, err := client.FetchItemType(misc.Logger, 587)
+	if err == nil {
+		t.Errorf("Expected error of failed lookup")
+	}
+
+	source.fail[587] = false
+
+	itemType, err := client.FetchItemType(misc.Logger, 587)
+	if err != nil || itemType.Name != "Type 587" {
+		t.Errorf("Expected lookup to be retried after failing, got %v (%v)", itemType, err)
+	}
+
+	if source.Requests(587) != 2 {
+		t.Errorf("Expected 2 requests, got %d", source.Requests(587))
+	}
+
+	if len(client.calls) != 0 {
+		t.Errorf("Expected no in-flight lookups, got %d", len(client.calls))
+	}
+}
+
+func TestCoalescingClientReleasesWaitersOnPanic(t *testing.T) {
+	source := newFakeClient(true)
+	source.panics = true
+	client := NewCoalescingClient(source)
+
+	panicked := make(chan interface{}, 1)
+
+	go func() {
+		defer func() {
+			panicked <- recover()
+		}()
+
+		client.FetchItemType(misc.Logger, 587)
+	}()
+
+	// Wait for the first lookup to be in flight before adding a waiting caller
+	for source.Requests(587) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiter := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				// The waiter started its own lookup after the first one finished, which panicked as well
+				waiter <- fmt.Errorf("%v", r)
+			}
+		}()
+
+		_, err := client.FetchItemType(misc.Logger, 587)
+		waiter <- err
+	}()
+
+	time.Sleep(time.Millisecond * 50)
+	close(source.release)
+
+	select {
+	case r := <-panicked:
+		if r == nil {
+			t.Errorf("Expected panic to be passed on to the caller")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Panicking lookup did not return")
+	}
+
+	select {
+	case err := <-waiter:
+		if err == nil {
+			t.Errorf("Expected waiting caller to receive an error")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Waiting caller was not released after the lookup panicked")
+	}
+
+	if len(client.calls) != 0 {
+		t.Errorf("Expected no in-flight lookups after panic, got %d", len(client.calls))
+	}
+}
+
+func TestResolveItemTypes(t *testing.T) {
+	source := newFakeClient(false)
+	source.fail[3] = true
+
+	itemTypes, err := ResolveItemTypes(misc.Logger, NewCoalescingClient(source), []int64{1, 2, 2, 3, 0, -1, 1}, 2)
+	if err == nil {
+		t.Errorf("Expected error for failed item type")
+	}
+
+	if len(itemTypes) != 2 || itemTypes[1] == nil || itemTypes[2] == nil {
+		t.Errorf("Expected item types 1 and 2 to be resolved, got %v", itemTypes)
+	}
+
+	for _, typeID := range []int64{1, 2, 3} {
+		if source.Requests(typeID) != 1 {
+			t.Errorf("Expected a single request for type #%d, got %d", typeID, source.Requests(typeID))
+		}
+	}
+
+	if source.Requests(0) != 0 || source.Requests(-1) != 0 {
+		t.Errorf("Expected invalid type IDs to be skipped")
+	}
+}
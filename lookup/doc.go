@@ -0,0 +1,3 @@
+// Package lookup provides access to the static universe data (item types, groups and locations) required by the application.
+// While presenting a high-level interface to the rest of the application, the package can use different data sources, such as the EVE Swagger Interface or the legacy CREST.
+package lookup
@@ -0,0 +1,397 @@
+package esi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/metrics"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	// DefaultRoot represents the root URL of the EVE Swagger Interface used if none was configured
+	DefaultRoot = "https://esi.evetech.net/latest"
+	// statusErrorLimited represents the HTTP status code returned by ESI once too many erroneous requests have been made
+	statusErrorLimited = 420
+)
+
+// cacheEntry stores a response received from ESI along with the caching information provided by the server
+type cacheEntry struct {
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// StatusError represents a non-OK HTTP status code received from ESI
+type StatusError struct {
+	Status     string
+	StatusCode int
+}
+
+// Error returns a description of the received status code
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Received non-OK HTTP status code %s (%d)", e.Status, e.StatusCode)
+}
+
+// Temporary returns whether the request may succeed if retried later, which is the case for server errors and exceeded rate or error limits
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode >= http.StatusInternalServerError || e.StatusCode == http.StatusTooManyRequests || e.StatusCode == statusErrorLimited
+}
+
+// Client is used for retrieving data from the EVE Swagger Interface and caching it locally according to the provided Expires and ETag headers, safe for concurrent use
+type Client struct {
+	mutex         sync.RWMutex
+	esiRoot       string
+	client        *http.Client
+	serverVersion string
+	cache         map[string]*cacheEntry
+}
+
+// NewClient creates a new Client with the given root URL
+func NewClient(root string) *Client {
+	c := &Client{
+		esiRoot:       strings.TrimSuffix(root, "/"),
+		client:        &http.Client{Timeout: time.Second * 30},
+		serverVersion: "",
+		cache:         make(map[string]*cacheEntry),
+	}
+
+	return c
+}
+
+// SetupClient creates a new Client using the ESI root of the given configuration, falling back to the default root if none was set
+func SetupClient(conf *misc.Configuration) *Client {
+	root := conf.ESIRoot
+	if len(root) == 0 {
+		root = DefaultRoot
+	}
+
+	return NewClient(root)
+}
+
+// FetchEndpoint retrieves the given ESI endpoint and returns the read data. Cached responses are returned until they expire, afterwards they are revalidated using their ETag
+func (c *Client) FetchEndpoint(log *misc.Log, url string) ([]byte, error) {
+	c.mutex.RLock()
+	entry, ok := c.cache[url]
+	c.mutex.RUnlock()
+
+	if ok && time.Now().Before(entry.expires) {
+		log.WithField("url", url).Tracef("Found response for ESI endpoint %q in cache", url)
+		metrics.LookupCacheHits.Inc("esi", "endpoint")
+		return entry.body, nil
+	}
+
+	metrics.LookupCacheMisses.Inc("esi", "endpoint")
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "eveslackkills github.com/morpheusxaut/eveslackkills")
+
+	if ok && len(entry.etag) > 0 {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		log.WithField("url", url).Tracef("ESI endpoint %q not modified, refreshing cache expiry", url)
+
+		c.mutex.Lock()
+		c.cache[url] = &cacheEntry{
+			body:    entry.body,
+			etag:    entry.etag,
+			expires: parseExpires(resp.Header),
+		}
+		c.mutex.Unlock()
+
+		return entry.body, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Status: resp.Status, StatusCode: resp.StatusCode}
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.cache[url] = &cacheEntry{
+		body:    response,
+		etag:    resp.Header.Get("ETag"),
+		expires: parseExpires(resp.Header),
+	}
+	c.mutex.Unlock()
+
+	return response, nil
+}
+
+// RefreshServerVersion retrieves the ESI status endpoint and stores the server version. Cached data is kept across server versions as it is revalidated using its ETag once expired
+func (c *Client) RefreshServerVersion(log *misc.Log) error {
+	response, err := c.FetchEndpoint(log, fmt.Sprintf("%s/status/", c.esiRoot))
+	if err != nil {
+		return err
+	}
+
+	var status *models.ESIStatus
+
+	err = json.Unmarshal(response, &status)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !strings.EqualFold(c.serverVersion, status.ServerVersion) {
+		log.Tracef("ESI server version changed (was %q, is %q)", c.serverVersion, status.ServerVersion)
+
+		c.serverVersion = status.ServerVersion
+	}
+
+	return nil
+}
+
+// ServerVersion returns the server version detected during the last refresh
+func (c *Client) ServerVersion() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.serverVersion
+}
+
+// FetchItemType retrieves the item type information for the given ID
+func (c *Client) FetchItemType(log *misc.Log, typeID int64) (*models.CRESTItemType, error) {
+	var itemType *models.ESIItemType
+
+	err := c.fetchJSON(log, fmt.Sprintf("%s/universe/types/%d/", c.esiRoot, typeID), &itemType)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.CRESTItemType{
+		Name:   itemType.Name,
+		Volume: itemType.Volume,
+		Group: models.CRESTReference{
+			ID:   itemType.GroupID,
+			Href: fmt.Sprintf("%s/universe/groups/%d/", c.esiRoot, itemType.GroupID),
+		},
+	}
+
+	return item, nil
+}
+
+// FetchItemGroup retrieves the item group information for the given ID, including all types belonging to the group
+func (c *Client) FetchItemGroup(log *misc.Log, groupID int64) (*models.CRESTItemGroup, error) {
+	var itemGroup *models.ESIItemGroup
+
+	err := c.fetchJSON(log, fmt.Sprintf("%s/universe/groups/%d/", c.esiRoot, groupID), &itemGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &models.CRESTItemGroup{
+		Name: itemGroup.Name,
+		Category: models.CRESTReference{
+			ID:   itemGroup.CategoryID,
+			Href: fmt.Sprintf("%s/universe/categories/%d/", c.esiRoot, itemGroup.CategoryID),
+		},
+		Types: make([]models.CRESTReference, 0, len(itemGroup.Types)),
+	}
+
+	for _, typeID := range itemGroup.Types {
+		group.Types = append(group.Types, models.CRESTReference{
+			ID:   typeID,
+			Href: fmt.Sprintf("%s/universe/types/%d/", c.esiRoot, typeID),
+		})
+	}
+
+	return group, nil
+}
+
+// FetchItemCategory retrieves the item category information for the given ID, including all groups belonging to the category
+func (c *Client) FetchItemCategory(log *misc.Log, categoryID int64) (*models.CRESTItemCategory, error) {
+	var itemCategory *models.ESIItemCategory
+
+	err := c.fetchJSON(log, fmt.Sprintf("%s/universe/categories/%d/", c.esiRoot, categoryID), &itemCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &models.CRESTItemCategory{
+		Name:   itemCategory.Name,
+		Groups: make([]models.CRESTReference, 0, len(itemCategory.Groups)),
+	}
+
+	for _, groupID := range itemCategory.Groups {
+		category.Groups = append(category.Groups, models.CRESTReference{
+			ID:   groupID,
+			Href: fmt.Sprintf("%s/universe/groups/%d/", c.esiRoot, groupID),
+		})
+	}
+
+	return category, nil
+}
+
+// FetchLocationInfo retrieves all available location info for the given solar system ID
+func (c *Client) FetchLocationInfo(log *misc.Log, systemID int64) (*models.CRESTLocationInfo, error) {
+	var system *models.ESISolarSystem
+
+	err := c.fetchJSON(log, fmt.Sprintf("%s/universe/systems/%d/", c.esiRoot, systemID), &system)
+	if err != nil {
+		return nil, err
+	}
+
+	var constellation *models.ESIConstellation
+
+	err = c.fetchJSON(log, fmt.Sprintf("%s/universe/constellations/%d/", c.esiRoot, system.ConstellationID), &constellation)
+	if err != nil {
+		return nil, err
+	}
+
+	var region *models.ESIRegion
+
+	err = c.fetchJSON(log, fmt.Sprintf("%s/universe/regions/%d/", c.esiRoot, constellation.RegionID), &region)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &models.CRESTLocationInfo{
+		SolarSystemID:       strconv.FormatInt(systemID, 10),
+		SolarSystemName:     system.Name,
+		SolarSystemSecurity: system.SecurityStatus,
+		ConstellationID:     strconv.FormatInt(system.ConstellationID, 10),
+		ConstellationName:   constellation.Name,
+		RegionID:            strconv.FormatInt(constellation.RegionID, 10),
+		RegionName:          region.Name,
+	}
+
+	return info, nil
+}
+
+// FetchNames resolves the names of the given IDs (characters, corporations, alliances, factions, types and locations)
+func (c *Client) FetchNames(ids []int64) ([]models.ESIName, error) {
+	body, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.request("POST", fmt.Sprintf("%s/universe/names/", c.esiRoot), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []models.ESIName
+
+	err = json.Unmarshal(response, &names)
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// FetchIDs resolves the given names of characters, corporations, alliances and solar systems to their IDs using ESI's bulk lookup. Names not matching exactly are omitted from the result
+func (c *Client) FetchIDs(names []string) (*models.ESIIDs, error) {
+	body, err := json.Marshal(names)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.request("POST", fmt.Sprintf("%s/universe/ids/", c.esiRoot), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids *models.ESIIDs
+
+	err = json.Unmarshal(response, &ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// FetchKillmail retrieves the full killmail with the given ID and hash. Killmails are not cached as they are only requested once
+func (c *Client) FetchKillmail(log *misc.Log, killID int64, hash string) (*models.ESIKillmail, error) {
+	response, err := c.request("GET", fmt.Sprintf("%s/killmails/%d/%s/", c.esiRoot, killID, hash), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var killmail *models.ESIKillmail
+
+	err = json.Unmarshal(response, &killmail)
+	if err != nil {
+		return nil, err
+	}
+
+	return killmail, nil
+}
+
+// request performs an uncached request to the given ESI endpoint and returns the read data
+func (c *Client) request(method string, url string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "eveslackkills github.com/morpheusxaut/eveslackkills")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Status: resp.Status, StatusCode: resp.StatusCode}
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// fetchJSON retrieves the given ESI endpoint and decodes the JSON response into the provided value
+func (c *Client) fetchJSON(log *misc.Log, url string, v interface{}) error {
+	response, err := c.FetchEndpoint(log, url)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(response, v)
+}
+
+// parseExpires parses the Expires header of an ESI response, returning the current time if the header is missing or invalid
+func parseExpires(header http.Header) time.Time {
+	expires, err := http.ParseTime(header.Get("Expires"))
+	if err != nil {
+		return time.Now()
+	}
+
+	return expires
+}
@@ -0,0 +1,2 @@
+// Package esi provides a lookup implementation retrieving static universe data from the EVE Swagger Interface.
+package esi
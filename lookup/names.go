@@ -0,0 +1,159 @@
+package lookup
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/morpheusxaut/eveslackkills/lookup/esi"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	// maxNamesPerRequest represents the maximum number of IDs resolved by a single bulk request
+	maxNamesPerRequest = 1000
+)
+
+// NameLookup provides an interface for resolving the names of characters, corporations, alliances, factions and other entities in bulk
+type NameLookup interface {
+	// FetchNames resolves the names of the given IDs
+	FetchNames(ids []int64) ([]models.ESIName, error)
+}
+
+// IDLookup provides an interface for resolving the IDs of characters, corporations, alliances and solar systems by their exact names
+type IDLookup interface {
+	// FetchIDs resolves the IDs of the given names
+	FetchIDs(names []string) (*models.ESIIDs, error)
+}
+
+// KillmailLookup provides an interface for retrieving full killmails by their ID and hash
+type KillmailLookup interface {
+	// FetchKillmail retrieves the full killmail with the given ID and hash
+	FetchKillmail(log *misc.Log, killID int64, hash string) (*models.ESIKillmail, error)
+}
+
+// NameResolver resolves IDs to names using bulk requests, caching all results locally. NameResolver is safe for concurrent use
+type NameResolver struct {
+	source NameLookup
+	mutex  sync.RWMutex
+	names  map[int64]string
+}
+
+// NewNameResolver creates a new NameResolver using the given source for bulk lookups
+func NewNameResolver(source NameLookup) *NameResolver {
+	r := &NameResolver{
+		source: source,
+		names:  make(map[int64]string),
+	}
+
+	return r
+}
+
+// Add stores the given name in the cache, allowing names already known from other sources to be reused
+func (r *NameResolver) Add(id int64, name string) {
+	if id <= 0 || len(name) == 0 {
+		return
+	}
+
+	r.mutex.Lock()
+	r.names[id] = name
+	r.mutex.Unlock()
+}
+
+// Name returns the cached name of the given ID or an empty string if it has not been resolved yet
+func (r *NameResolver) Name(id int64) string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.names[id]
+}
+
+// Resolve retrieves the names of all given IDs, only querying IDs not found in the cache.
+// All successfully resolved names are returned, along with the first error encountered
+func (r *NameResolver) Resolve(ids []int64) (map[int64]string, error) {
+	names := make(map[int64]string, len(ids))
+	missing := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+
+	r.mutex.RLock()
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+
+		name, ok := r.names[id]
+		if ok {
+			names[id] = name
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	r.mutex.RUnlock()
+
+	var firstErr error
+
+	for start := 0; start < len(missing); start += maxNamesPerRequest {
+		end := start + maxNamesPerRequest
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		err := r.fetch(missing[start:end], names)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return names, firstErr
+}
+
+// fetch resolves the given IDs and stores the results in the cache and provided map.
+// As a single invalid ID causes the whole request to be rejected, rejected requests are split up to resolve as many names as possible. Other errors are returned immediately
+func (r *NameResolver) fetch(ids []int64, names map[int64]string) error {
+	misc.Logger.Tracef("Resolving names for %d IDs", len(ids))
+
+	resolved, err := r.source.FetchNames(ids)
+	if err != nil {
+		if !isRejected(err) {
+			misc.Logger.Debugf("Failed to resolve names for %d IDs: [%v]", len(ids), err)
+			return err
+		}
+
+		if len(ids) == 1 {
+			misc.Logger.Debugf("Failed to resolve name for ID #%d: [%v]", ids[0], err)
+			return err
+		}
+
+		middle := len(ids) / 2
+
+		errFirst := r.fetch(ids[:middle], names)
+		errSecond := r.fetch(ids[middle:], names)
+
+		if errFirst != nil {
+			return errFirst
+		}
+
+		return errSecond
+	}
+
+	r.mutex.Lock()
+	for _, name := range resolved {
+		r.names[name.ID] = name.Name
+		names[name.ID] = name.Name
+	}
+	r.mutex.Unlock()
+
+	return nil
+}
+
+// isRejected checks whether the given error was caused by ESI rejecting the request due to invalid IDs, in which case splitting up the request may succeed
+func isRejected(err error) bool {
+	statusErr, ok := err.(*esi.StatusError)
+	if !ok {
+		return false
+	}
+
+	return statusErr.StatusCode == http.StatusBadRequest || statusErr.StatusCode == http.StatusNotFound
+}
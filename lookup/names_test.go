@@ -0,0 +1,109 @@
+package lookup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/kdar/factorlog"
+
+	"github.com/morpheusxaut/eveslackkills/lookup/esi"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+func init() {
+	misc.Logger = misc.NewLog(ioutil.Discard, misc.LogFormatText, factorlog.CRITICAL)
+}
+
+// fakeNameSource resolves names for all IDs, rejecting requests containing invalid IDs or failing all requests with a fixed error
+type fakeNameSource struct {
+	invalid  map[int64]bool
+	err      error
+	requests int
+}
+
+// FetchNames resolves the given IDs, counting the number of requests made
+func (s *fakeNameSource) FetchNames(ids []int64) ([]models.ESIName, error) {
+	s.requests++
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	names := make([]models.ESIName, 0, len(ids))
+	for _, id := range ids {
+		if s.invalid[id] {
+			return nil, &esi.StatusError{Status: "404 Not Found", StatusCode: http.StatusNotFound}
+		}
+
+		names = append(names, models.ESIName{ID: id, Name: fmt.Sprintf("Name %d", id)})
+	}
+
+	return names, nil
+}
+
+func TestNameResolverSplitsRejectedRequests(t *testing.T) {
+	source := &fakeNameSource{invalid: map[int64]bool{3: true}}
+	resolver := NewNameResolver(source)
+
+	names, err := resolver.Resolve([]int64{1, 2, 3, 4})
+	if err == nil {
+		t.Errorf("Expected error for invalid ID")
+	}
+
+	resolved := make([]int64, 0, len(names))
+	for id := range names {
+		resolved = append(resolved, id)
+	}
+	sort.Sort(int64s(resolved))
+
+	if fmt.Sprint(resolved) != "[1 2 4]" {
+		t.Errorf("Expected IDs [1 2 4] to be resolved, got %v", resolved)
+	}
+
+	// [1 2 3 4] -> [1 2] + [3 4] -> [3] + [4]
+	if source.requests != 5 {
+		t.Errorf("Expected 5 requests, got %d", source.requests)
+	}
+
+	if resolver.Name(4) != "Name 4" {
+		t.Errorf("Expected resolved name to be cached, got %q", resolver.Name(4))
+	}
+}
+
+func TestNameResolverReturnsOtherErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "server error", err: &esi.StatusError{Status: "502 Bad Gateway", StatusCode: http.StatusBadGateway}},
+		{name: "rate limited", err: &esi.StatusError{Status: "429 Too Many Requests", StatusCode: http.StatusTooManyRequests}},
+		{name: "network error", err: fmt.Errorf("connection refused")},
+	}
+
+	for _, test := range tests {
+		source := &fakeNameSource{err: test.err}
+		resolver := NewNameResolver(source)
+
+		names, err := resolver.Resolve([]int64{1, 2, 3, 4, 5, 6, 7, 8})
+		if err != test.err {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.err, err)
+		}
+		if len(names) != 0 {
+			t.Errorf("%s: expected no names, got %v", test.name, names)
+		}
+		if source.requests != 1 {
+			t.Errorf("%s: expected a single request, got %d", test.name, source.requests)
+		}
+	}
+}
+
+// int64s sorts a slice of int64 values in ascending order
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
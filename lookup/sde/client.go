@@ -0,0 +1,188 @@
+package sde
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/morpheusxaut/eveslackkills/database"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// Client is used for retrieving static universe data imported from the Static Data Export and caching it locally, safe for concurrent use
+type Client struct {
+	mutex        sync.RWMutex
+	database     database.Connection
+	itemTypes    map[int64]*models.CRESTItemType
+	itemGroups   map[int64]*models.CRESTItemGroup
+	categories   map[int64]*models.CRESTItemCategory
+	locationInfo map[int64]*models.CRESTLocationInfo
+}
+
+// NewClient creates a new Client reading static data from the given database connection
+func NewClient(db database.Connection) *Client {
+	c := &Client{
+		database:     db,
+		itemTypes:    make(map[int64]*models.CRESTItemType),
+		itemGroups:   make(map[int64]*models.CRESTItemGroup),
+		categories:   make(map[int64]*models.CRESTItemCategory),
+		locationInfo: make(map[int64]*models.CRESTLocationInfo),
+	}
+
+	return c
+}
+
+// RefreshServerVersion does nothing as imported static data only changes when a new import is performed
+func (c *Client) RefreshServerVersion(log *misc.Log) error {
+	return nil
+}
+
+// ServerVersion returns a static version as imported static data is not tied to a server version
+func (c *Client) ServerVersion() string {
+	return "sde"
+}
+
+// FetchItemType retrieves the item type information for the given ID
+func (c *Client) FetchItemType(log *misc.Log, typeID int64) (*models.CRESTItemType, error) {
+	c.mutex.RLock()
+	item, ok := c.itemTypes[typeID]
+	c.mutex.RUnlock()
+
+	if ok {
+		log.Tracef("Found item type for type ID #%d in cache", typeID)
+		return item, nil
+	}
+
+	itemType, err := c.database.LoadStaticItemType(typeID)
+	if err != nil {
+		return nil, err
+	}
+
+	item = &models.CRESTItemType{
+		Name:   itemType.Name,
+		Volume: itemType.Volume,
+		Group: models.CRESTReference{
+			ID: itemType.GroupID,
+		},
+	}
+
+	c.mutex.Lock()
+	c.itemTypes[typeID] = item
+	c.mutex.Unlock()
+
+	return item, nil
+}
+
+// FetchItemGroup retrieves the item group information for the given ID, including all types belonging to the group
+func (c *Client) FetchItemGroup(log *misc.Log, groupID int64) (*models.CRESTItemGroup, error) {
+	c.mutex.RLock()
+	group, ok := c.itemGroups[groupID]
+	c.mutex.RUnlock()
+
+	if ok {
+		log.Tracef("Found item group for group ID #%d in cache", groupID)
+		return group, nil
+	}
+
+	itemGroup, err := c.database.LoadStaticItemGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	group = &models.CRESTItemGroup{
+		Name: itemGroup.Name,
+		Category: models.CRESTReference{
+			ID: itemGroup.CategoryID,
+		},
+		Types: make([]models.CRESTReference, 0, len(itemGroup.Types)),
+	}
+
+	for _, typeID := range itemGroup.Types {
+		group.Types = append(group.Types, models.CRESTReference{
+			ID: typeID,
+		})
+	}
+
+	c.mutex.Lock()
+	c.itemGroups[groupID] = group
+	c.mutex.Unlock()
+
+	return group, nil
+}
+
+// FetchItemCategory retrieves the item category information for the given ID, including all groups belonging to the category
+func (c *Client) FetchItemCategory(log *misc.Log, categoryID int64) (*models.CRESTItemCategory, error) {
+	c.mutex.RLock()
+	category, ok := c.categories[categoryID]
+	c.mutex.RUnlock()
+
+	if ok {
+		log.Tracef("Found item category for category ID #%d in cache", categoryID)
+		return category, nil
+	}
+
+	itemCategory, err := c.database.LoadStaticItemCategory(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	category = &models.CRESTItemCategory{
+		Name:   itemCategory.Name,
+		Groups: make([]models.CRESTReference, 0, len(itemCategory.Groups)),
+	}
+
+	for _, groupID := range itemCategory.Groups {
+		category.Groups = append(category.Groups, models.CRESTReference{
+			ID: groupID,
+		})
+	}
+
+	c.mutex.Lock()
+	c.categories[categoryID] = category
+	c.mutex.Unlock()
+
+	return category, nil
+}
+
+// FetchLocationInfo retrieves all available location info for the given solar system ID
+func (c *Client) FetchLocationInfo(log *misc.Log, systemID int64) (*models.CRESTLocationInfo, error) {
+	c.mutex.RLock()
+	info, ok := c.locationInfo[systemID]
+	c.mutex.RUnlock()
+
+	if ok {
+		log.Tracef("Found location info for solar system #%d in cache", systemID)
+		return info, nil
+	}
+
+	system, err := c.database.LoadStaticSolarSystem(systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	constellation, err := c.database.LoadStaticConstellation(system.ConstellationID)
+	if err != nil {
+		return nil, err
+	}
+
+	region, err := c.database.LoadStaticRegion(system.RegionID)
+	if err != nil {
+		return nil, err
+	}
+
+	info = &models.CRESTLocationInfo{
+		SolarSystemID:       strconv.FormatInt(systemID, 10),
+		SolarSystemName:     system.Name,
+		SolarSystemSecurity: system.Security,
+		ConstellationID:     strconv.FormatInt(system.ConstellationID, 10),
+		ConstellationName:   constellation.Name,
+		RegionID:            strconv.FormatInt(system.RegionID, 10),
+		RegionName:          region.Name,
+	}
+
+	c.mutex.Lock()
+	c.locationInfo[systemID] = info
+	c.mutex.Unlock()
+
+	return info, nil
+}
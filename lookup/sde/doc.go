@@ -0,0 +1,2 @@
+// Package sde provides an importer for the Static Data Export and a lookup implementation serving static universe data from the imported copy without requiring network access.
+package sde
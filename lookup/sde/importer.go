@@ -0,0 +1,292 @@
+package sde
+
+import (
+	"compress/bzip2"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	// DefaultSource represents the default location of the Static Data Export, using the CSV conversion provided by Fuzzwork
+	DefaultSource = "https://www.fuzzwork.co.uk/dump/latest/"
+)
+
+// Importer reads the tables required by the application from a CSV conversion of the Static Data Export, either stored in a local directory or provided via HTTP
+type Importer struct {
+	source string
+	client *http.Client
+}
+
+// NewImporter creates a new Importer reading from the given local directory or base URL
+func NewImporter(source string) *Importer {
+	i := &Importer{
+		source: source,
+		client: &http.Client{Timeout: time.Minute * 5},
+	}
+
+	return i
+}
+
+// Import reads all required tables from the source, returning an error if a table could not be read or parsed
+func (i *Importer) Import() (*models.SDEData, error) {
+	data := &models.SDEData{}
+
+	types, err := i.readTable("invTypes")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range types {
+		data.ItemTypes = append(data.ItemTypes, models.SDEItemType{
+			TypeID:  row.Int("typeID"),
+			GroupID: row.Int("groupID"),
+			Name:    row.String("typeName"),
+			Volume:  row.Float("volume"),
+		})
+
+		if row.err != nil {
+			return nil, row.err
+		}
+	}
+
+	groups, err := i.readTable("invGroups")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range groups {
+		data.ItemGroups = append(data.ItemGroups, models.SDEItemGroup{
+			GroupID:    row.Int("groupID"),
+			CategoryID: row.Int("categoryID"),
+			Name:       row.String("groupName"),
+		})
+
+		if row.err != nil {
+			return nil, row.err
+		}
+	}
+
+	categories, err := i.readTable("invCategories")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range categories {
+		data.ItemCategories = append(data.ItemCategories, models.SDEItemCategory{
+			CategoryID: row.Int("categoryID"),
+			Name:       row.String("categoryName"),
+		})
+
+		if row.err != nil {
+			return nil, row.err
+		}
+	}
+
+	solarSystems, err := i.readTable("mapSolarSystems")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range solarSystems {
+		data.SolarSystems = append(data.SolarSystems, models.SDESolarSystem{
+			SolarSystemID:   row.Int("solarSystemID"),
+			ConstellationID: row.Int("constellationID"),
+			RegionID:        row.Int("regionID"),
+			Name:            row.String("solarSystemName"),
+			Security:        row.Float("security"),
+		})
+
+		if row.err != nil {
+			return nil, row.err
+		}
+	}
+
+	constellations, err := i.readTable("mapConstellations")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range constellations {
+		data.Constellations = append(data.Constellations, models.SDEConstellation{
+			ConstellationID: row.Int("constellationID"),
+			RegionID:        row.Int("regionID"),
+			Name:            row.String("constellationName"),
+		})
+
+		if row.err != nil {
+			return nil, row.err
+		}
+	}
+
+	regions, err := i.readTable("mapRegions")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range regions {
+		data.Regions = append(data.Regions, models.SDERegion{
+			RegionID: row.Int("regionID"),
+			Name:     row.String("regionName"),
+		})
+
+		if row.err != nil {
+			return nil, row.err
+		}
+	}
+
+	return data, nil
+}
+
+// readTable reads the CSV file of the given table, returning every row along with its position in the table
+func (i *Importer) readTable(table string) ([]*tableRow, error) {
+	misc.Logger.Debugf("Reading SDE table %q from %q", table, i.source)
+
+	reader, err := i.open(table)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	csvReader := csv.NewReader(reader)
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read header of table %q: %v", table, err)
+	}
+
+	var rows []*tableRow
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed to read table %q: %v", table, err)
+		}
+
+		row := &tableRow{
+			table:  table,
+			index:  len(rows) + 1,
+			values: make(map[string]string, len(header)),
+		}
+
+		for idx, column := range header {
+			if idx < len(record) {
+				row.values[column] = record[idx]
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	misc.Logger.Tracef("Read %d rows from SDE table %q", len(rows), table)
+
+	return rows, nil
+}
+
+// open opens the CSV file of the given table, downloading and decompressing it if the source is an URL
+func (i *Importer) open(table string) (io.ReadCloser, error) {
+	if strings.HasPrefix(i.source, "http://") || strings.HasPrefix(i.source, "https://") {
+		url := fmt.Sprintf("%s/%s.csv.bz2", strings.TrimSuffix(i.source, "/"), table)
+
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("User-Agent", "eveslackkills github.com/morpheusxaut/eveslackkills")
+
+		resp, err := i.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("Received non-OK HTTP status code %s (%d)", resp.Status, resp.StatusCode)
+		}
+
+		return &compressedReader{
+			Reader: bzip2.NewReader(resp.Body),
+			body:   resp.Body,
+		}, nil
+	}
+
+	return os.Open(filepath.Join(i.source, fmt.Sprintf("%s.csv", table)))
+}
+
+// compressedReader wraps a decompressing reader, closing the underlying response body
+type compressedReader struct {
+	io.Reader
+	body io.Closer
+}
+
+// Close closes the underlying response body
+func (r *compressedReader) Close() error {
+	return r.body.Close()
+}
+
+// tableRow represents a single row of a SDE table, numbered from 1 after the header since values may span multiple lines of the file, keeping the first error encountered while parsing its values
+type tableRow struct {
+	table  string
+	index  int
+	values map[string]string
+	err    error
+}
+
+// String returns the value of the given column, recording an error if the column is missing
+func (r *tableRow) String(column string) string {
+	value, ok := r.values[column]
+	if !ok && r.err == nil {
+		r.err = fmt.Errorf("Missing column %q in table %q, row %d", column, r.table, r.index)
+	}
+
+	return value
+}
+
+// Int parses the value of the given column as an integer, returning 0 for NULL values and recording an error for invalid ones
+func (r *tableRow) Int(column string) int64 {
+	value := strings.TrimSpace(r.String(column))
+	if isNull(value) {
+		return 0
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("Invalid integer %q in column %q of table %q, row %d", value, column, r.table, r.index)
+	}
+
+	return parsed
+}
+
+// Float parses the value of the given column as a floating point number, returning 0 for NULL values and recording an error for invalid ones
+func (r *tableRow) Float(column string) float64 {
+	value := strings.TrimSpace(r.String(column))
+	if isNull(value) {
+		return 0
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("Invalid number %q in column %q of table %q, row %d", value, column, r.table, r.index)
+	}
+
+	return parsed
+}
+
+// isNull checks whether the given CSV value represents NULL, which is exported as an empty value or "None"
+func isNull(value string) bool {
+	return len(value) == 0 || value == "None"
+}
@@ -0,0 +1,27 @@
+package lookup
+
+// Type represents the type of lookup backend to use
+type Type int
+
+const (
+	// TypeESI represents the EVE Swagger Interface as a lookup backend
+	TypeESI Type = iota
+	// TypeCREST represents the legacy EVE CREST as a lookup backend (shut down by CCP)
+	TypeCREST
+	// TypeSDE represents a copy of the Static Data Export imported into the database as a lookup backend
+	TypeSDE
+)
+
+// String returns a easily readable string representations of the given Type
+func (t Type) String() string {
+	switch t {
+	case TypeESI:
+		return "ESI"
+	case TypeCREST:
+		return "CREST"
+	case TypeSDE:
+		return "SDE"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Counter represents a monotonically increasing value, partitioned by the given label names
+type Counter struct {
+	set *seriesSet
+}
+
+// NewCounter creates a new Counter with the given name, help text and label names and registers it with the default registry
+func NewCounter(name string, help string, labelNames ...string) *Counter {
+	c := &Counter{
+		set: newSeriesSet(name, help, "counter", labelNames),
+	}
+
+	Default.Register(c)
+
+	return c
+}
+
+// Inc increments the counter with the given label values by one
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increments the counter with the given label values by the provided, non-negative delta
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+
+	c.set.mutex.Lock()
+	defer c.set.mutex.Unlock()
+
+	entry := c.set.get(labelValues, func() interface{} { return new(float64) })
+	*entry.value.(*float64) += delta
+}
+
+// Write appends the help text, type and all samples of the counter to the given buffer
+func (c *Counter) Write(buf *bytes.Buffer) {
+	c.set.write(buf, func(entry *series) {
+		fmt.Fprintf(buf, "%s%s %s\n", c.set.name, formatLabels(c.set.labelNames, entry.labelValues), formatValue(*entry.value.(*float64)))
+	})
+}
@@ -0,0 +1,3 @@
+// Package metrics collects counters, gauges and histograms describing the application's activity and exposes them in the Prometheus text exposition format.
+// All metrics used by the application are registered with the default registry, which is served by the admin server at "/metrics".
+package metrics
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Gauge represents a value which can arbitrarily go up and down, partitioned by the given label names
+type Gauge struct {
+	set *seriesSet
+}
+
+// NewGauge creates a new Gauge with the given name, help text and label names and registers it with the default registry
+func NewGauge(name string, help string, labelNames ...string) *Gauge {
+	g := &Gauge{
+		set: newSeriesSet(name, help, "gauge", labelNames),
+	}
+
+	Default.Register(g)
+
+	return g
+}
+
+// Set sets the gauge with the given label values to the provided value
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.set.mutex.Lock()
+	defer g.set.mutex.Unlock()
+
+	entry := g.set.get(labelValues, func() interface{} { return new(float64) })
+	*entry.value.(*float64) = value
+}
+
+// Write appends the help text, type and all samples of the gauge to the given buffer
+func (g *Gauge) Write(buf *bytes.Buffer) {
+	g.set.write(buf, func(entry *series) {
+		fmt.Fprintf(buf, "%s%s %s\n", g.set.name, formatLabels(g.set.labelNames, entry.labelValues), formatValue(*entry.value.(*float64)))
+	})
+}
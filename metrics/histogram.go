@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultBuckets represents the upper bounds in seconds used for histograms measuring the duration of requests
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// UpdateBuckets represents the upper bounds in seconds used for histograms measuring the duration of update cycles, which include waiting for Slack's message limit
+var UpdateBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 900, 1800}
+
+// histogramValue stores the observations of a single time series of a histogram
+type histogramValue struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Histogram represents the distribution of observed values sorted into buckets, partitioned by the given label names
+type Histogram struct {
+	set     *seriesSet
+	buckets []float64
+}
+
+// NewHistogram creates a new Histogram with the given name, help text, bucket upper bounds and label names and registers it with the default registry
+func NewHistogram(name string, help string, buckets []float64, labelNames ...string) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	h := &Histogram{
+		set:     newSeriesSet(name, help, "histogram", labelNames),
+		buckets: sorted,
+	}
+
+	Default.Register(h)
+
+	return h
+}
+
+// Observe adds the given value to the histogram with the provided label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.set.mutex.Lock()
+	defer h.set.mutex.Unlock()
+
+	entry := h.set.get(labelValues, func() interface{} {
+		return &histogramValue{
+			counts: make([]uint64, len(h.buckets)),
+		}
+	})
+
+	histogram := entry.value.(*histogramValue)
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			histogram.counts[i]++
+		}
+	}
+
+	histogram.count++
+	histogram.sum += value
+}
+
+// ObserveSince adds the time elapsed since the given start in seconds to the histogram with the provided label values
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Write appends the help text, type and all buckets, sums and counts of the histogram to the given buffer
+func (h *Histogram) Write(buf *bytes.Buffer) {
+	h.set.write(buf, func(entry *series) {
+		histogram := entry.value.(*histogramValue)
+
+		for i, bound := range h.buckets {
+			fmt.Fprintf(buf, "%s_bucket%s %d\n", h.set.name, formatLabels(h.set.labelNames, entry.labelValues, "le", formatValue(bound)), histogram.counts[i])
+		}
+
+		fmt.Fprintf(buf, "%s_bucket%s %d\n", h.set.name, formatLabels(h.set.labelNames, entry.labelValues, "le", "+Inf"), histogram.count)
+		fmt.Fprintf(buf, "%s_sum%s %s\n", h.set.name, formatLabels(h.set.labelNames, entry.labelValues), formatValue(histogram.sum))
+		fmt.Fprintf(buf, "%s_count%s %d\n", h.set.name, formatLabels(h.set.labelNames, entry.labelValues), histogram.count)
+	})
+}
@@ -0,0 +1,27 @@
+package metrics
+
+var (
+	// ZKillboardRequests counts the requests sent to zKillboard by HTTP status code, using "error" for failed requests
+	ZKillboardRequests = NewCounter("eveslackkills_zkillboard_requests_total", "Number of requests sent to zKillboard by HTTP status code.", "status")
+	// ZKillboardRequestDuration measures the duration of requests sent to zKillboard
+	ZKillboardRequestDuration = NewHistogram("eveslackkills_zkillboard_request_duration_seconds", "Duration of requests sent to zKillboard.", DefaultBuckets)
+	// ZKillboardEntries counts the kills and losses retrieved from zKillboard by type
+	ZKillboardEntries = NewCounter("eveslackkills_zkillboard_entries_total", "Number of kills and losses retrieved from zKillboard by type.", "type")
+
+	// LookupCacheHits counts the lookups answered from a cache by cache and kind of data
+	LookupCacheHits = NewCounter("eveslackkills_lookup_cache_hits_total", "Number of lookups answered from a cache by cache and kind of data.", "cache", "kind")
+	// LookupCacheMisses counts the lookups requiring a request to the lookup source by cache and kind of data
+	LookupCacheMisses = NewCounter("eveslackkills_lookup_cache_misses_total", "Number of lookups not found in a cache by cache and kind of data.", "cache", "kind")
+
+	// SlackPosts counts the messages sent to Slack by destination and result
+	SlackPosts = NewCounter("eveslackkills_slack_posts_total", "Number of messages sent to Slack by destination and result.", "destination", "result")
+	// SlackPostDuration measures the duration of requests sent to Slack by destination
+	SlackPostDuration = NewHistogram("eveslackkills_slack_post_duration_seconds", "Duration of requests sent to Slack by destination.", DefaultBuckets, "destination")
+
+	// UpdateDuration measures the duration of a single update cycle by EVE corporation ID
+	UpdateDuration = NewHistogram("eveslackkills_update_duration_seconds", "Duration of a single update cycle by EVE corporation ID.", UpdateBuckets, "corporation")
+	// RetryQueueSize represents the number of messages currently waiting to be retried
+	RetryQueueSize = NewGauge("eveslackkills_retry_queue_size", "Number of messages waiting to be retried after Slack failed.")
+	// Posts counts the messages posted by EVE corporation ID and type (kill, loss, battle, ...)
+	Posts = NewCounter("eveslackkills_posts_total", "Number of messages posted by EVE corporation ID and type.", "corporation", "type")
+)
@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collector provides an interface for metrics which can be written in the Prometheus text exposition format
+type Collector interface {
+	// Write appends the help text, type and all samples of the metric to the given buffer
+	Write(buf *bytes.Buffer)
+}
+
+// Registry stores all collectors exposed together, safe for concurrent use
+type Registry struct {
+	mutex      sync.RWMutex
+	collectors []Collector
+}
+
+// Default represents the registry all metrics of the application are registered with
+var Default = NewRegistry()
+
+// NewRegistry creates a new, empty Registry
+func NewRegistry() *Registry {
+	r := &Registry{
+		collectors: make([]Collector, 0),
+	}
+
+	return r
+}
+
+// Register adds the given collector to the registry
+func (r *Registry) Register(collector Collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.collectors = append(r.collectors, collector)
+}
+
+// Bytes returns all registered metrics in the Prometheus text exposition format
+func (r *Registry) Bytes() []byte {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var buf bytes.Buffer
+
+	for _, collector := range r.collectors {
+		collector.Write(&buf)
+	}
+
+	return buf.Bytes()
+}
+
+// ServeHTTP responds with all registered metrics in the Prometheus text exposition format
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(r.Bytes())
+}
+
+// series stores the label values of a single time series along with its sample, shared by all metric types
+type series struct {
+	labelValues []string
+	value       interface{}
+}
+
+// seriesSet stores all time series of a metric, keyed by their label values
+type seriesSet struct {
+	mutex      sync.RWMutex
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+	series     map[string]*series
+}
+
+// newSeriesSet creates a new, empty seriesSet for the metric with the given name, help text, type and label names
+func newSeriesSet(name string, help string, metricType string, labelNames []string) *seriesSet {
+	s := &seriesSet{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		labelNames: labelNames,
+		series:     make(map[string]*series),
+	}
+
+	return s
+}
+
+// get returns the time series with the given label values, creating it using the provided function if it does not exist yet. The caller must hold the write lock
+func (s *seriesSet) get(labelValues []string, create func() interface{}) *series {
+	if len(labelValues) != len(s.labelNames) {
+		panic(fmt.Sprintf("Metric %q expects %d label values, got %d", s.name, len(s.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	entry, ok := s.series[key]
+	if !ok {
+		entry = &series{
+			labelValues: append([]string(nil), labelValues...),
+			value:       create(),
+		}
+		s.series[key] = entry
+	}
+
+	return entry
+}
+
+// write appends the help text and type of the metric to the given buffer, calling the provided function for every time series ordered by their label values
+func (s *seriesSet) write(buf *bytes.Buffer, fn func(entry *series)) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	fmt.Fprintf(buf, "# HELP %s %s\n", s.name, escapeHelp(s.help))
+	fmt.Fprintf(buf, "# TYPE %s %s\n", s.name, s.metricType)
+
+	keys := make([]string, 0, len(s.series))
+	for key := range s.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fn(s.series[key])
+	}
+}
+
+// formatLabels returns the label set of a sample using the given names and values, appending an additional label if provided
+func formatLabels(names []string, values []string, extra ...string) string {
+	pairs := make([]string, 0, len(names)+1)
+
+	for i, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", name, escapeLabelValue(values[i])))
+	}
+
+	if len(extra) == 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", extra[0], escapeLabelValue(extra[1])))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ","))
+}
+
+// formatValue returns the given sample value as expected by the text exposition format
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escapeHelp escapes backslashes and line feeds in the given help text
+func escapeHelp(help string) string {
+	return strings.NewReplacer("\\", "\\\\", "\n", "\\n").Replace(help)
+}
+
+// escapeLabelValue escapes backslashes, double quotes and line feeds in the given label value
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(value)
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegistryBytes(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := &Counter{
+		set: newSeriesSet("test_requests_total", "Number of requests by status.\nSecond line with \\ backslash.", "counter", []string{"status"}),
+	}
+	registry.Register(counter)
+
+	gauge := &Gauge{
+		set: newSeriesSet("test_queue_size", "Number of queued items.", "gauge", nil),
+	}
+	registry.Register(gauge)
+
+	histogram := &Histogram{
+		set:     newSeriesSet("test_duration_seconds", "Duration of requests.", "histogram", []string{"destination"}),
+		buckets: []float64{0.5, 1, 2.5},
+	}
+	registry.Register(histogram)
+
+	counter.Inc("200")
+	counter.Add(2, "200")
+	counter.Add(-1, "200")
+	counter.Inc("error \"quoted\"\nnext")
+	counter.Add(1500000, "500")
+
+	gauge.Set(3)
+	gauge.Set(1.5)
+
+	histogram.Observe(0.25, "webhook")
+	histogram.Observe(1, "webhook")
+	histogram.Observe(5, "webhook")
+	histogram.Observe(0.75, "api")
+
+	expected := `# HELP test_requests_total Number of requests by status.\nSecond line with \\ backslash.
+# TYPE test_requests_total counter
+test_requests_total{status="200"} 3
+test_requests_total{status="500"} 1.5e+06
+test_requests_total{status="error \"quoted\"\nnext"} 1
+# HELP test_queue_size Number of queued items.
+# TYPE test_queue_size gauge
+test_queue_size 1.5
+# HELP test_duration_seconds Duration of requests.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{destination="api",le="0.5"} 0
+test_duration_seconds_bucket{destination="api",le="1"} 1
+test_duration_seconds_bucket{destination="api",le="2.5"} 1
+test_duration_seconds_bucket{destination="api",le="+Inf"} 1
+test_duration_seconds_sum{destination="api"} 0.75
+test_duration_seconds_count{destination="api"} 1
+test_duration_seconds_bucket{destination="webhook",le="0.5"} 1
+test_duration_seconds_bucket{destination="webhook",le="1"} 2
+test_duration_seconds_bucket{destination="webhook",le="2.5"} 2
+test_duration_seconds_bucket{destination="webhook",le="+Inf"} 3
+test_duration_seconds_sum{destination="webhook"} 6.25
+test_duration_seconds_count{destination="webhook"} 3
+`
+
+	if got := string(registry.Bytes()); got != expected {
+		t.Errorf("expected exposition:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestRegistryBytesEmptySeries(t *testing.T) {
+	registry := NewRegistry()
+
+	registry.Register(&Counter{
+		set: newSeriesSet("test_empty_total", "Counter without samples.", "counter", []string{"type"}),
+	})
+
+	expected := "# HELP test_empty_total Counter without samples.\n# TYPE test_empty_total counter\n"
+
+	if got := string(registry.Bytes()); got != expected {
+		t.Errorf("expected exposition:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	registry := NewRegistry()
+
+	gauge := &Gauge{
+		set: newSeriesSet("test_queue_size", "Number of queued items.", "gauge", nil),
+	}
+	registry.Register(gauge)
+
+	gauge.Set(2)
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("expected text exposition content type, got %q", contentType)
+	}
+
+	expected := "# HELP test_queue_size Number of queued items.\n# TYPE test_queue_size gauge\ntest_queue_size 2\n"
+
+	if got := recorder.Body.String(); got != expected {
+		t.Errorf("expected exposition:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestSeriesSetLabelCountMismatch(t *testing.T) {
+	counter := &Counter{
+		set: newSeriesSet("test_requests_total", "Number of requests by status.", "counter", []string{"status"}),
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for mismatching label values, got none")
+		}
+	}()
+
+	counter.Inc()
+}
@@ -19,16 +19,74 @@ type Configuration struct {
 	DatabaseUser string
 	// DatabasePassword represents the password used to authenticate with the database backend
 	DatabasePassword string
-	// DebugLevel represents the debug level for log messages
+	// LookupType represents the source of static universe data (0 = ESI, 1 = CREST, 2 = imported SDE)
+	LookupType int
+	// ESIRoot represents the root URL of the EVE Swagger Interface used for static data, names and killmails, leave empty to use the official ESI
+	ESIRoot string
+	// LookupConcurrency represents the maximum number of simultaneous lookup requests performed while resolving a killmail
+	LookupConcurrency int
+	// PriceSourceType represents the source of item prices used to value kills (0 = zKillboard values, 1 = local price table, 2 = HTTP source)
+	PriceSourceType int
+	// PriceTableFile represents the path to a CSV or JSON encoded price table used to value items if the price table source is used
+	PriceTableFile string
+	// PriceURL represents the URL of the HTTP price source in the ESI market prices format, leave empty to use ESI
+	PriceURL string
+	// PriceRefreshInterval represents the interval in minutes between refreshes of the item prices
+	PriceRefreshInterval int
+	// ShowFitting represents whether kill and loss messages should include a summary of the most valuable fitted modules
+	ShowFitting bool
+	// ZKillboardUserAgent represents the User-Agent sent to zKillboard, which should include contact information as requested by its API documentation
+	ZKillboardUserAgent string
+	// ZKillboardTimeout represents the timeout in seconds for a single request to zKillboard
+	ZKillboardTimeout int
+	// ZKillboardRequestsPerSecond represents the number of requests per second sent to zKillboard across all corporations, negative values disable the limit
+	ZKillboardRequestsPerSecond float64
+	// ZKillboardBurst represents the number of requests which may be sent to zKillboard at once before the rate limit applies
+	ZKillboardBurst int
+	// DebugLevel represents the legacy numeric debug level for log messages, only used if no LogLevel was set
 	DebugLevel int
+	// LogLevel represents the minimum level of log messages (trace, debug, info, warn, error or critical)
+	LogLevel string
+	// LogFormat represents the format of log messages, either "text" or "json" for one JSON object per line
+	LogFormat string
+	// LogFile represents the path to the file log messages are appended to, leave empty to log to stdout
+	LogFile string
 	// SlackWebhookURL represents the webhook URL provided by slack, used by the application to send chat messages
 	SlackWebhookURL string
+	// SlackAPIToken represents the optional Slack API token, allowing the application to update previously sent messages
+	SlackAPIToken string
+	// SlackChannel represents the channel used to send messages via the Slack API, only required if an API token was set
+	SlackChannel string
+	// AdminListenAddress represents the address (host:port) the admin API listens on, leave empty to disable it
+	AdminListenAddress string
+	// AdminToken represents the token required as bearer token for all requests to the admin API
+	AdminToken string
+	// HealthMaxUpdateAge represents the time in minutes without progress of the update loop after which the bot is reported as unhealthy, 0 uses three times the update interval
+	HealthMaxUpdateAge int
+	// BacklogSummaryThreshold represents the number of new kills and losses found during a single update above which a summary is posted instead of individual messages, 0 disables summaries
+	BacklogSummaryThreshold int
+	// SlackSigningSecret represents the signing secret of the Slack app, used to verify slash commands and interactions sent to the admin server, leave empty to disable them
+	SlackSigningSecret string
+	// SlackIgnoreAllowList represents the Slack user IDs or channel IDs allowed to ignore solar systems via Slack, nobody is allowed if left empty
+	SlackIgnoreAllowList []string
+	// BattleMinimumKills represents the number of kills and losses in a solar system required to aggregate them into a battle report
+	BattleMinimumKills int
+	// BattleWindow represents the maximum time in minutes between two kills of the same battle
+	BattleWindow int
+	// DailyDigest represents whether a summary of the previous day should be posted for every corporation
+	DailyDigest bool
+	// WeeklyDigest represents whether a summary of the previous week should be posted for every corporation every Monday
+	WeeklyDigest bool
+	// DigestHour represents the hour (EVE time) at which scheduled reports are posted
+	DigestHour int
+	// LeaderboardInterval represents the interval (daily, weekly or monthly) at which pilot leaderboards are posted, leave empty to disable them
+	LeaderboardInterval string
 }
 
 // LoadConfig creates a Configuration by either using commandline flags or a configuration file, returning an error if the parsing failed
 func LoadConfig() (*Configuration, error) {
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: eveslackkills [options]\n")
+		fmt.Fprintf(os.Stderr, "Usage: eveslackkills [options] [command [arguments]]\n")
 		flag.PrintDefaults()
 		os.Exit(2)
 	}
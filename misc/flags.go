@@ -4,6 +4,7 @@ import "flag"
 
 var (
 	debugLevelFlag = flag.Int("debug", 3, "Sets the debug level (0-9), lower number displays more messages")
+	logLevelFlag   = flag.String("loglevel", "", "Sets the minimum level of log messages (trace, debug, info, warn, error, critical), overriding the debug level")
 	configFileFlag = flag.String("config", "config.cfg", "Path to the config file to parse")
 )
 
@@ -12,6 +13,9 @@ func ParseCommandlineFlags(config *Configuration) *Configuration {
 	if *debugLevelFlag != 3 {
 		config.DebugLevel = *debugLevelFlag
 	}
+	if len(*logLevelFlag) > 0 {
+		config.LogLevel = *logLevelFlag
+	}
 
 	return config
 }
@@ -1,18 +1,308 @@
 package misc
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/kdar/factorlog"
 )
 
+const (
+	// LogFormatText represents human-readable log messages, formatted by factorlog
+	LogFormatText = "text"
+	// LogFormatJSON represents log messages encoded as one JSON object per line, including all context fields as separate keys
+	LogFormatJSON = "json"
+	// logCallDepth represents the number of stack frames between the caller of a logging method and the method writing the message
+	logCallDepth = 3
+)
+
 var (
 	// Logger is a accessible logger, providing formatted log messages with different output levels
-	Logger *factorlog.FactorLog
+	Logger *Log
+
+	// logOutput holds the log file opened by SetupLogger, allowing it to be reopened after being rotated
+	logOutput *logFile
+
+	// logLevels maps the level names accepted in the configuration to their severity
+	logLevels = map[string]factorlog.Severity{
+		"trace":    factorlog.TRACE,
+		"debug":    factorlog.DEBUG,
+		"info":     factorlog.INFO,
+		"warn":     factorlog.WARN,
+		"warning":  factorlog.WARN,
+		"error":    factorlog.ERROR,
+		"critical": factorlog.CRITICAL,
+	}
 )
 
-// SetupLogger configures the logger, setting a custom format and output level
-func SetupLogger(debugLevel int) {
-	Logger = factorlog.New(os.Stdout, factorlog.NewStdFormatter("[%{Date} %{Time}] {%{SEVERITY}:%{File}/%{PkgFunction}:%{Line}} %{SafeMessage}"))
-	Logger.SetMinMaxSeverity(factorlog.Severity(1<<uint(debugLevel)), factorlog.PANIC)
+// Fields represents context attached to log messages, such as the corporation or kill ID being processed
+type Fields map[string]interface{}
+
+// Log writes log messages of at least the configured level either as text or JSON lines, attaching the context fields of the logger. Loggers derived using With share their output and are safe for concurrent use
+type Log struct {
+	mutex  *sync.Mutex
+	format string
+	level  factorlog.Severity
+	output io.Writer
+	text   *factorlog.FactorLog
+	fields Fields
+}
+
+// SetupLogger configures the logger using the log level, format and output file of the given configuration, returning an error if a setting is invalid or the file could not be opened
+func SetupLogger(conf *Configuration) error {
+	level, err := ParseLogLevel(conf.LogLevel, conf.DebugLevel)
+	if err != nil {
+		return err
+	}
+
+	format := strings.ToLower(conf.LogFormat)
+	if len(format) == 0 {
+		format = LogFormatText
+	}
+
+	if format != LogFormatText && format != LogFormatJSON {
+		return fmt.Errorf("Unknown log format %q", conf.LogFormat)
+	}
+
+	var output io.Writer = os.Stdout
+
+	if len(conf.LogFile) > 0 {
+		file, err := openLogFile(conf.LogFile)
+		if err != nil {
+			return err
+		}
+
+		logOutput = file
+		output = file
+	}
+
+	Logger = NewLog(output, format, level)
+
+	return nil
+}
+
+// ReopenLogFile closes and reopens the configured log file, allowing log messages to be written to a new file after the old one was moved by logrotate. Logging to stdout is not affected
+func ReopenLogFile() error {
+	if logOutput == nil {
+		return nil
+	}
+
+	return logOutput.Reopen()
+}
+
+// NewLog creates a new Log writing messages of at least the given level in the provided format to the writer
+func NewLog(output io.Writer, format string, level factorlog.Severity) *Log {
+	l := &Log{
+		mutex:  &sync.Mutex{},
+		format: format,
+		level:  level,
+		output: output,
+		fields: Fields{},
+	}
+
+	if format != LogFormatJSON {
+		l.text = factorlog.New(output, factorlog.NewStdFormatter("[%{Date} %{Time}] {%{SEVERITY}:%{File}/%{PkgFunction}:%{Line}} %{SafeMessage}"))
+		l.text.SetMinMaxSeverity(level, factorlog.PANIC)
+	}
+
+	return l
+}
+
+// logFile represents a log file which can be reopened while loggers are writing to it
+type logFile struct {
+	mutex sync.Mutex
+	path  string
+	file  *os.File
+}
+
+// openLogFile opens the log file at the given path for appending, creating it if it does not exist yet
+func openLogFile(path string) (*logFile, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logFile{
+		path: path,
+		file: file,
+	}, nil
+}
+
+// Write appends the given data to the currently opened file
+func (f *logFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.file.Write(p)
+}
+
+// Reopen opens the log file again and closes the previous file, continuing to write to the previous file if opening fails
+func (f *logFile) Reopen() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	previous := f.file
+	f.file = file
+	f.mutex.Unlock()
+
+	return previous.Close()
+}
+
+// ParseLogLevel returns the severity belonging to the given level name, falling back to the legacy numeric debug level if no name was set
+func ParseLogLevel(name string, debugLevel int) (factorlog.Severity, error) {
+	if len(name) == 0 {
+		return factorlog.Severity(1 << uint(debugLevel)), nil
+	}
+
+	level, ok := logLevels[strings.ToLower(name)]
+	if !ok {
+		return factorlog.NONE, fmt.Errorf("Unknown log level %q", name)
+	}
+
+	return level, nil
+}
+
+// With returns a logger attaching the given fields in addition to the fields of the current logger to all messages
+func (l *Log) With(fields Fields) *Log {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	derived := *l
+	derived.fields = merged
+
+	return &derived
+}
+
+// WithField returns a logger attaching the given field in addition to the fields of the current logger to all messages
+func (l *Log) WithField(key string, value interface{}) *Log {
+	return l.With(Fields{key: value})
+}
+
+// Tracef logs a formatted message with trace level
+func (l *Log) Tracef(format string, v ...interface{}) {
+	l.logf(factorlog.TRACE, format, v...)
+}
+
+// Debugf logs a formatted message with debug level
+func (l *Log) Debugf(format string, v ...interface{}) {
+	l.logf(factorlog.DEBUG, format, v...)
+}
+
+// Infof logs a formatted message with info level
+func (l *Log) Infof(format string, v ...interface{}) {
+	l.logf(factorlog.INFO, format, v...)
+}
+
+// Warnf logs a formatted message with warning level
+func (l *Log) Warnf(format string, v ...interface{}) {
+	l.logf(factorlog.WARN, format, v...)
+}
+
+// Errorf logs a formatted message with error level
+func (l *Log) Errorf(format string, v ...interface{}) {
+	l.logf(factorlog.ERROR, format, v...)
+}
+
+// Criticalf logs a formatted message with critical level
+func (l *Log) Criticalf(format string, v ...interface{}) {
+	l.logf(factorlog.CRITICAL, format, v...)
+}
+
+// logf formats and writes a message with the given severity if it is at least the configured level
+func (l *Log) logf(severity factorlog.Severity, format string, v ...interface{}) {
+	if severity < l.level {
+		return
+	}
+
+	message := fmt.Sprintf(format, v...)
+
+	if l.format != LogFormatJSON {
+		if len(l.fields) > 0 {
+			message = fmt.Sprintf("%s %s", message, l.formatFields())
+		}
+
+		l.text.Output(severity, logCallDepth, message)
+		return
+	}
+
+	entry := make(map[string]interface{}, len(l.fields)+4)
+	for key, value := range l.fields {
+		entry[key] = value
+	}
+
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = severityName(severity)
+	entry["msg"] = message
+
+	_, file, line, ok := runtime.Caller(logCallDepth - 1)
+	if ok {
+		entry["caller"] = fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(file)), filepath.Base(file), line)
+	}
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		encoded, _ = json.Marshal(map[string]interface{}{
+			"time":  entry["time"],
+			"level": entry["level"],
+			"msg":   fmt.Sprintf("%s (failed to encode fields: %v)", message, err),
+		})
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.output.Write(append(encoded, '\n'))
+}
+
+// formatFields returns the fields of the logger as sorted key=value pairs, used for text messages
+func (l *Log) formatFields() string {
+	keys := make([]string, 0, len(l.fields))
+	for key := range l.fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, l.fields[key]))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(pairs, " "))
+}
+
+// severityName returns the level name of the given severity as used in JSON log messages
+func severityName(severity factorlog.Severity) string {
+	switch severity {
+	case factorlog.TRACE:
+		return "trace"
+	case factorlog.DEBUG:
+		return "debug"
+	case factorlog.INFO:
+		return "info"
+	case factorlog.WARN:
+		return "warn"
+	case factorlog.ERROR:
+		return "error"
+	case factorlog.CRITICAL:
+		return "critical"
+	default:
+		return "unknown"
+	}
 }
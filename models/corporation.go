@@ -1,13 +1,24 @@
 package models
 
+const (
+	// DefaultKillComment represents the kill comment used for newly added corporations
+	DefaultKillComment = "{killername} killed {victimname} ({victimshipname})"
+	// DefaultLossComment represents the loss comment used for newly added corporations
+	DefaultLossComment = "{victimname} lost a {victimshipname} to {killername}"
+)
+
 // Corporation represents an EVE corporation to be tracked by the application
 type Corporation struct {
-	ID                  int64
-	EVECorporationID    int64
-	LastKillID          int64
-	LastLossID          int64
-	Name                string
-	KillComment         string
-	LossComment         string
-	IgnoredSolarSystems []int64
+	ID                  int64   `json:"id"`
+	EVECorporationID    int64   `json:"eveCorporationID"`
+	LastKillID          int64   `json:"lastKillID"`
+	LastLossID          int64   `json:"lastLossID"`
+	Name                string  `json:"name"`
+	KillComment         string  `json:"killComment"`
+	LossComment         string  `json:"lossComment"`
+	AlertValue          float64 `json:"alertValue"`
+	AlertStructures     bool    `json:"alertStructures"`
+	AlertMention        string  `json:"alertMention"`
+	IgnoredSolarSystems []int64 `json:"ignoredSolarSystems"`
+	AlertShipGroups     []int64 `json:"alertShipGroups"`
 }
@@ -6,7 +6,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 
+	"github.com/morpheusxaut/eveslackkills/metrics"
 	"github.com/morpheusxaut/eveslackkills/misc"
 )
 
@@ -23,10 +25,31 @@ type CRESTHref struct {
 	Href string `json:"href"`
 }
 
+// CRESTReference represents a link resource including the ID and name of the referenced item as provided by the EVE CREST
+type CRESTReference struct {
+	ID   int64  `json:"id"`
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
 // CRESTItemType represents information about an item type as provided by the EVE CREST
 type CRESTItemType struct {
-	Name   string  `json:"name"`
-	Volume float64 `json:"volume"`
+	Name   string         `json:"name"`
+	Volume float64        `json:"volume"`
+	Group  CRESTReference `json:"group"`
+}
+
+// CRESTItemGroup represents information about an item group and the types belonging to it as provided by the EVE CREST
+type CRESTItemGroup struct {
+	Name     string           `json:"name"`
+	Category CRESTReference   `json:"category"`
+	Types    []CRESTReference `json:"types"`
+}
+
+// CRESTItemCategory represents information about an item category and the groups belonging to it as provided by the EVE CREST
+type CRESTItemCategory struct {
+	Name   string           `json:"name"`
+	Groups []CRESTReference `json:"groups"`
 }
 
 // CRESTSolarSystem represents information about a solar system as provided by the EVE CREST
@@ -60,12 +83,15 @@ type CRESTLocationInfo struct {
 	RegionName          string
 }
 
-// CRESTClient is used for retrieving data from the EVE CREST and caching it locally
+// CRESTClient is used for retrieving data from the EVE CREST and caching it locally, safe for concurrent use
 type CRESTClient struct {
+	mutex         sync.RWMutex
 	crestRoot     string
 	client        *http.Client
 	serverVersion string
 	itemTypes     map[int64]*CRESTItemType
+	itemGroups    map[int64]*CRESTItemGroup
+	categories    map[int64]*CRESTItemCategory
 	locationInfo  map[int64]*CRESTLocationInfo
 }
 
@@ -76,6 +102,8 @@ func NewCRESTClient(root string) *CRESTClient {
 		client:        &http.Client{},
 		serverVersion: "",
 		itemTypes:     make(map[int64]*CRESTItemType),
+		itemGroups:    make(map[int64]*CRESTItemGroup),
+		categories:    make(map[int64]*CRESTItemCategory),
 		locationInfo:  make(map[int64]*CRESTLocationInfo),
 	}
 
@@ -112,16 +140,33 @@ func (c *CRESTClient) FetchEndpoint(url string) ([]byte, error) {
 }
 
 // CheckServerVersion compares the stored and provided server version, invalidating cached data if a change has been detected
-func (c *CRESTClient) CheckServerVersion(version string) {
+func (c *CRESTClient) CheckServerVersion(log *misc.Log, version string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if !strings.EqualFold(c.serverVersion, version) {
-		misc.Logger.Tracef("CREST server version changed (was %q, is %q), deleting cached data", c.serverVersion, version)
+		log.Tracef("CREST server version changed (was %q, is %q), deleting cached data", c.serverVersion, version)
 
 		c.itemTypes = make(map[int64]*CRESTItemType)
+		c.itemGroups = make(map[int64]*CRESTItemGroup)
+		c.categories = make(map[int64]*CRESTItemCategory)
 		c.locationInfo = make(map[int64]*CRESTLocationInfo)
 		c.serverVersion = version
 	}
 }
 
+// RefreshServerVersion retrieves the root CREST endpoint and checks the server version, invalidating cached data if a change has been detected
+func (c *CRESTClient) RefreshServerVersion(log *misc.Log) error {
+	root, err := c.FetchRoot()
+	if err != nil {
+		return err
+	}
+
+	c.CheckServerVersion(log, root.ServerVersion)
+
+	return nil
+}
+
 // FetchRoot retrieves the root CREST endpoint
 func (c *CRESTClient) FetchRoot() (*CRESTRoot, error) {
 	response, err := c.FetchEndpoint(c.crestRoot)
@@ -139,15 +184,29 @@ func (c *CRESTClient) FetchRoot() (*CRESTRoot, error) {
 	return root, nil
 }
 
+// ServerVersion returns the server version detected during the last refresh
+func (c *CRESTClient) ServerVersion() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.serverVersion
+}
+
 // FetchItemType retrieves the item type information for the given ID
-func (c *CRESTClient) FetchItemType(typeID int64) (*CRESTItemType, error) {
+func (c *CRESTClient) FetchItemType(log *misc.Log, typeID int64) (*CRESTItemType, error) {
+	c.mutex.RLock()
 	item, ok := c.itemTypes[typeID]
+	c.mutex.RUnlock()
+
 	if ok {
-		misc.Logger.Tracef("Found item type for type ID #%d in cache", typeID)
+		log.Tracef("Found item type for type ID #%d in cache", typeID)
+		metrics.LookupCacheHits.Inc("crest", "itemtype")
 		return item, nil
 	}
 
-	misc.Logger.Tracef("Querying CREST for item type #%d", typeID)
+	metrics.LookupCacheMisses.Inc("crest", "itemtype")
+
+	log.Tracef("Querying CREST for item type #%d", typeID)
 
 	response, err := c.FetchEndpoint(fmt.Sprintf("%s/types/%d/", c.crestRoot, typeID))
 	if err != nil {
@@ -159,20 +218,94 @@ func (c *CRESTClient) FetchItemType(typeID int64) (*CRESTItemType, error) {
 		return nil, err
 	}
 
+	c.mutex.Lock()
 	c.itemTypes[typeID] = item
+	c.mutex.Unlock()
 
 	return item, nil
 }
 
+// FetchItemGroup retrieves the item group information for the given ID, including all types belonging to the group
+func (c *CRESTClient) FetchItemGroup(log *misc.Log, groupID int64) (*CRESTItemGroup, error) {
+	c.mutex.RLock()
+	group, ok := c.itemGroups[groupID]
+	c.mutex.RUnlock()
+
+	if ok {
+		log.Tracef("Found item group for group ID #%d in cache", groupID)
+		metrics.LookupCacheHits.Inc("crest", "itemgroup")
+		return group, nil
+	}
+
+	metrics.LookupCacheMisses.Inc("crest", "itemgroup")
+
+	log.Tracef("Querying CREST for item group #%d", groupID)
+
+	response, err := c.FetchEndpoint(fmt.Sprintf("%s/inventory/groups/%d/", c.crestRoot, groupID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &group)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.itemGroups[groupID] = group
+	c.mutex.Unlock()
+
+	return group, nil
+}
+
+// FetchItemCategory retrieves the item category information for the given ID, including all groups belonging to the category
+func (c *CRESTClient) FetchItemCategory(log *misc.Log, categoryID int64) (*CRESTItemCategory, error) {
+	c.mutex.RLock()
+	category, ok := c.categories[categoryID]
+	c.mutex.RUnlock()
+
+	if ok {
+		log.Tracef("Found item category for category ID #%d in cache", categoryID)
+		metrics.LookupCacheHits.Inc("crest", "itemcategory")
+		return category, nil
+	}
+
+	metrics.LookupCacheMisses.Inc("crest", "itemcategory")
+
+	log.Tracef("Querying CREST for item category #%d", categoryID)
+
+	response, err := c.FetchEndpoint(fmt.Sprintf("%s/inventory/categories/%d/", c.crestRoot, categoryID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &category)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.categories[categoryID] = category
+	c.mutex.Unlock()
+
+	return category, nil
+}
+
 // FetchLocationInfo retrieves all available location info for the given solar system ID
-func (c *CRESTClient) FetchLocationInfo(systemID int64) (*CRESTLocationInfo, error) {
+func (c *CRESTClient) FetchLocationInfo(log *misc.Log, systemID int64) (*CRESTLocationInfo, error) {
+	c.mutex.RLock()
 	info, ok := c.locationInfo[systemID]
+	c.mutex.RUnlock()
+
 	if ok {
-		misc.Logger.Tracef("Found location info for solar system #%d in cache", systemID)
+		log.Tracef("Found location info for solar system #%d in cache", systemID)
+		metrics.LookupCacheHits.Inc("crest", "locationinfo")
 		return info, nil
 	}
 
-	misc.Logger.Tracef("Querying CREST for location info for solar system #%d", systemID)
+	metrics.LookupCacheMisses.Inc("crest", "locationinfo")
+
+	log.Tracef("Querying CREST for location info for solar system #%d", systemID)
 
 	system, err := c.FetchSolarSystem(systemID)
 	if err != nil {
@@ -213,7 +346,9 @@ func (c *CRESTClient) FetchLocationInfo(systemID int64) (*CRESTLocationInfo, err
 		RegionName:          region.Name,
 	}
 
+	c.mutex.Lock()
 	c.locationInfo[systemID] = info
+	c.mutex.Unlock()
 
 	return info, nil
 }
@@ -0,0 +1,119 @@
+package models
+
+// ESIStatus represents the server status as provided by the EVE Swagger Interface
+type ESIStatus struct {
+	ServerVersion string `json:"server_version"`
+	Players       int64  `json:"players"`
+}
+
+// ESIItemType represents information about an item type as provided by the EVE Swagger Interface
+type ESIItemType struct {
+	TypeID  int64   `json:"type_id"`
+	Name    string  `json:"name"`
+	Volume  float64 `json:"volume"`
+	GroupID int64   `json:"group_id"`
+}
+
+// ESIItemGroup represents information about an item group as provided by the EVE Swagger Interface
+type ESIItemGroup struct {
+	GroupID    int64   `json:"group_id"`
+	Name       string  `json:"name"`
+	CategoryID int64   `json:"category_id"`
+	Types      []int64 `json:"types"`
+}
+
+// ESIItemCategory represents information about an item category as provided by the EVE Swagger Interface
+type ESIItemCategory struct {
+	CategoryID int64   `json:"category_id"`
+	Name       string  `json:"name"`
+	Groups     []int64 `json:"groups"`
+}
+
+// ESISolarSystem represents information about a solar system as provided by the EVE Swagger Interface
+type ESISolarSystem struct {
+	SystemID        int64   `json:"system_id"`
+	Name            string  `json:"name"`
+	SecurityStatus  float64 `json:"security_status"`
+	ConstellationID int64   `json:"constellation_id"`
+}
+
+// ESIConstellation represents information about a constellation as provided by the EVE Swagger Interface
+type ESIConstellation struct {
+	ConstellationID int64   `json:"constellation_id"`
+	Name            string  `json:"name"`
+	RegionID        int64   `json:"region_id"`
+	Systems         []int64 `json:"systems"`
+}
+
+// ESIRegion represents information about a region as provided by the EVE Swagger Interface
+type ESIRegion struct {
+	RegionID       int64   `json:"region_id"`
+	Name           string  `json:"name"`
+	Constellations []int64 `json:"constellations"`
+}
+
+// ESIName represents the name and category resolved for an ID as provided by the EVE Swagger Interface
+type ESIName struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// ESIIDs represents the IDs resolved for a list of names as provided by the EVE Swagger Interface, grouped by category
+type ESIIDs struct {
+	Alliances    []ESIName `json:"alliances"`
+	Characters   []ESIName `json:"characters"`
+	Corporations []ESIName `json:"corporations"`
+	Systems      []ESIName `json:"systems"`
+}
+
+// ESIKillmail represents a killmail as provided by the EVE Swagger Interface
+type ESIKillmail struct {
+	KillmailID    int64                 `json:"killmail_id"`
+	KillmailTime  string                `json:"killmail_time"`
+	SolarSystemID int64                 `json:"solar_system_id"`
+	MoonID        int64                 `json:"moon_id"`
+	Victim        ESIKillmailVictim     `json:"victim"`
+	Attackers     []ESIKillmailAttacker `json:"attackers"`
+}
+
+// ESIKillmailVictim represents the victim of a killmail as provided by the EVE Swagger Interface
+type ESIKillmailVictim struct {
+	CharacterID   int64             `json:"character_id"`
+	CorporationID int64             `json:"corporation_id"`
+	AllianceID    int64             `json:"alliance_id"`
+	FactionID     int64             `json:"faction_id"`
+	ShipTypeID    int64             `json:"ship_type_id"`
+	DamageTaken   int64             `json:"damage_taken"`
+	Items         []ESIKillmailItem `json:"items"`
+}
+
+// ESIKillmailAttacker represents an attacker of a killmail as provided by the EVE Swagger Interface
+type ESIKillmailAttacker struct {
+	CharacterID    int64   `json:"character_id"`
+	CorporationID  int64   `json:"corporation_id"`
+	AllianceID     int64   `json:"alliance_id"`
+	FactionID      int64   `json:"faction_id"`
+	ShipTypeID     int64   `json:"ship_type_id"`
+	WeaponTypeID   int64   `json:"weapon_type_id"`
+	DamageDone     int64   `json:"damage_done"`
+	FinalBlow      bool    `json:"final_blow"`
+	SecurityStatus float64 `json:"security_status"`
+}
+
+// ESIKillmailItem represents an item of a killmail as provided by the EVE Swagger Interface, possibly containing further items
+type ESIKillmailItem struct {
+	ItemTypeID        int64             `json:"item_type_id"`
+	Flag              int64             `json:"flag"`
+	QuantityDropped   int64             `json:"quantity_dropped"`
+	QuantityDestroyed int64             `json:"quantity_destroyed"`
+	Singleton         int64             `json:"singleton"`
+	Items             []ESIKillmailItem `json:"items"`
+}
+
+// ESIMarketPrice represents the average and adjusted price of an item type as provided by the EVE Swagger Interface
+type ESIMarketPrice struct {
+	TypeID        int64   `json:"type_id"`
+	AveragePrice  float64 `json:"average_price"`
+	AdjustedPrice float64 `json:"adjusted_price"`
+}
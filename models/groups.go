@@ -0,0 +1,10 @@
+package models
+
+var (
+	// CapitalShipGroupIDs contains the item group IDs of all capital ships (Carrier, Dreadnought, Force Auxiliary, Capital Industrial Ship)
+	CapitalShipGroupIDs = []int64{547, 485, 1538, 883}
+	// SupercapitalShipGroupIDs contains the item group IDs of all supercapital ships (Supercarrier, Titan)
+	SupercapitalShipGroupIDs = []int64{659, 30}
+	// StructureCategoryIDs contains the item category IDs of all deployable structures (Starbase, Sovereignty Structures, Structure)
+	StructureCategoryIDs = []int64{23, 40, 65}
+)
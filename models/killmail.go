@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Killmail represents a kill or loss stored in the killmail history of a tracked corporation
+type Killmail struct {
+	ID            int64
+	CorporationID int64
+	KillID        int64
+	Type          ZKillboardEntryType
+	KillTime      time.Time
+	SolarSystemID int64
+	TotalValue    float64
+	Reviewed      bool
+	ReviewedBy    string
+	Entry         ZKillboardEntry
+}
+
+// NewKillmail creates a new killmail for the given corporation from the provided timeline entry, returning an error if the kill time could not be parsed
+func NewKillmail(corporation *Corporation, item ZKillboardTimelineEntry) (*Killmail, error) {
+	killTime, err := item.Entry.ParseKillTime()
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Killmail{
+		CorporationID: corporation.ID,
+		KillID:        item.Entry.KillID,
+		Type:          item.Type,
+		KillTime:      killTime,
+		SolarSystemID: item.Entry.SolarSystemID,
+		TotalValue:    item.Entry.Misc.TotalValue,
+		Entry:         item.Entry,
+	}
+
+	return k, nil
+}
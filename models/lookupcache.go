@@ -0,0 +1,9 @@
+package models
+
+// LookupCacheEntry represents a single resolved lookup result persisted to avoid repeated requests after restarts
+type LookupCacheEntry struct {
+	Kind    string
+	ID      int64
+	Version string
+	Data    string
+}
@@ -0,0 +1,56 @@
+package models
+
+// SDEItemType represents an item type as imported from the Static Data Export
+type SDEItemType struct {
+	TypeID  int64
+	GroupID int64
+	Name    string
+	Volume  float64
+}
+
+// SDEItemGroup represents an item group as imported from the Static Data Export
+type SDEItemGroup struct {
+	GroupID    int64
+	CategoryID int64
+	Name       string
+	Types      []int64
+}
+
+// SDEItemCategory represents an item category as imported from the Static Data Export
+type SDEItemCategory struct {
+	CategoryID int64
+	Name       string
+	Groups     []int64
+}
+
+// SDESolarSystem represents a solar system as imported from the Static Data Export
+type SDESolarSystem struct {
+	SolarSystemID   int64
+	ConstellationID int64
+	RegionID        int64
+	Name            string
+	Security        float64
+}
+
+// SDEConstellation represents a constellation as imported from the Static Data Export
+type SDEConstellation struct {
+	ConstellationID int64
+	RegionID        int64
+	Name            string
+}
+
+// SDERegion represents a region as imported from the Static Data Export
+type SDERegion struct {
+	RegionID int64
+	Name     string
+}
+
+// SDEData stores all static data imported from the Static Data Export
+type SDEData struct {
+	ItemTypes      []SDEItemType
+	ItemGroups     []SDEItemGroup
+	ItemCategories []SDEItemCategory
+	SolarSystems   []SDESolarSystem
+	Constellations []SDEConstellation
+	Regions        []SDERegion
+}
@@ -1,18 +1,39 @@
 package models
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/metrics"
+	"github.com/morpheusxaut/eveslackkills/misc"
+)
+
 // SlackPayload represents the payload to be sent to the Slack web hook
 type SlackPayload struct {
-	Attachments []SlackAttachment `json:"attachments"`
+	Channel         string            `json:"channel,omitempty"`
+	Timestamp       string            `json:"ts,omitempty"`
+	ResponseType    string            `json:"response_type,omitempty"`
+	ReplaceOriginal bool              `json:"replace_original"`
+	Text            string            `json:"text,omitempty"`
+	Attachments     []SlackAttachment `json:"attachments"`
 }
 
 // SlackAttachment represents a single attachment as sent as a Slack payload
 type SlackAttachment struct {
-	Title     string       `json:"title,omitempty"`
-	TitleLink string       `json:"title_link,omitempty"`
-	ThumbURL  string       `json:"thumb_url,omitempty"`
-	Fallback  string       `json:"fallback,omitempty"`
-	Color     string       `json:"color,omitempty"`
-	Fields    []SlackField `json:"fields,omitempty"`
+	Title      string        `json:"title,omitempty"`
+	TitleLink  string        `json:"title_link,omitempty"`
+	ThumbURL   string        `json:"thumb_url,omitempty"`
+	Fallback   string        `json:"fallback,omitempty"`
+	Color      string        `json:"color,omitempty"`
+	Text       string        `json:"text,omitempty"`
+	Fields     []SlackField  `json:"fields,omitempty"`
+	CallbackID string        `json:"callback_id,omitempty"`
+	Actions    []SlackAction `json:"actions,omitempty"`
 }
 
 // SlackField represents a single field used within Slack attachments for formatting
@@ -21,3 +42,223 @@ type SlackField struct {
 	Value string `json:"value,omitempty"`
 	Short bool   `json:"short"`
 }
+
+// SlackAction represents a button attached to a Slack message, also used for the action received when a user clicked it
+type SlackAction struct {
+	Name  string `json:"name"`
+	Text  string `json:"text,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+	Style string `json:"style,omitempty"`
+}
+
+// SlackUser represents the Slack user triggering an interaction
+type SlackUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// SlackChannel represents the Slack channel an interaction originated from
+type SlackChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// SlackInteraction represents the payload sent by Slack after a user clicked a button attached to a message
+type SlackInteraction struct {
+	Type        string        `json:"type"`
+	CallbackID  string        `json:"callback_id"`
+	ResponseURL string        `json:"response_url"`
+	User        SlackUser     `json:"user"`
+	Channel     SlackChannel  `json:"channel"`
+	Actions     []SlackAction `json:"actions"`
+}
+
+// SlackResponse represents the response received from the Slack Web API after posting or updating a message
+type SlackResponse struct {
+	OK        bool   `json:"ok"`
+	Error     string `json:"error"`
+	Channel   string `json:"channel"`
+	Timestamp string `json:"ts"`
+}
+
+// SlackClient is used for sending messages to Slack, either via a web hook or the Web API
+type SlackClient struct {
+	mutex       sync.RWMutex
+	webhookURL  string
+	apiToken    string
+	channel     string
+	client      *http.Client
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// NewSlackClient creates a new SlackClient with the given web hook URL. If an API token and channel are provided, messages are sent via the Web API instead
+func NewSlackClient(webhookURL string, apiToken string, channel string) *SlackClient {
+	c := &SlackClient{
+		webhookURL: webhookURL,
+		apiToken:   apiToken,
+		channel:    channel,
+		client:     &http.Client{},
+	}
+
+	return c
+}
+
+// CanUpdate returns whether the client is able to update previously sent messages, requiring the usage of the Web API
+func (c *SlackClient) CanUpdate() bool {
+	return len(c.apiToken) > 0 && len(c.channel) > 0
+}
+
+// PostPayload sends the given payload to Slack, returning the channel and timestamp of the new message if the Web API is used
+func (c *SlackClient) PostPayload(payload *SlackPayload) (*SlackResponse, error) {
+	start := time.Now()
+
+	if !c.CanUpdate() {
+		err := c.post(c.webhookURL, payload)
+		c.observe("webhook", start, err)
+		if err != nil {
+			return nil, err
+		}
+
+		return &SlackResponse{OK: true}, nil
+	}
+
+	payload.Channel = c.channel
+	payload.Timestamp = ""
+
+	response, err := c.postAPI("https://slack.com/api/chat.postMessage", payload)
+	c.observe("api", start, err)
+
+	return response, err
+}
+
+// UpdatePayload replaces the message with the given channel and timestamp with the provided payload
+func (c *SlackClient) UpdatePayload(channel string, timestamp string, payload *SlackPayload) (*SlackResponse, error) {
+	if !c.CanUpdate() {
+		return nil, fmt.Errorf("Updating messages requires a Slack API token and channel")
+	}
+
+	payload.Channel = channel
+	payload.Timestamp = timestamp
+
+	start := time.Now()
+
+	response, err := c.postAPI("https://slack.com/api/chat.update", payload)
+	c.observe("update", start, err)
+
+	return response, err
+}
+
+// PostResponse sends the given payload as a delayed response to a slash command or interaction using the provided response URL
+func (c *SlackClient) PostResponse(responseURL string, payload *SlackPayload) error {
+	start := time.Now()
+
+	err := c.post(responseURL, payload)
+	c.observe("response", start, err)
+
+	return err
+}
+
+func (c *SlackClient) post(url string, payload *SlackPayload) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("Failed to send message: %s (status code: %d)", string(respBody), resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *SlackClient) postAPI(url string, payload *SlackPayload) (*SlackResponse, error) {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiToken))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Failed to send message: %s (status code: %d)", string(respBody), resp.StatusCode)
+	}
+
+	var response *SlackResponse
+
+	err = json.Unmarshal(respBody, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.OK {
+		return nil, fmt.Errorf("Failed to send message: %s", response.Error)
+	}
+
+	return response, nil
+}
+
+// LastError returns the most recent error encountered while sending a message to Slack along with the time it occurred, an empty string if no message failed yet
+func (c *SlackClient) LastError() (string, time.Time) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.lastError, c.lastErrorAt
+}
+
+// observe records the result and duration of a request sent to the given Slack destination, storing the error if the request failed
+func (c *SlackClient) observe(destination string, start time.Time, err error) {
+	metrics.SlackPostDuration.ObserveSince(start, destination)
+
+	logger := misc.Logger.WithField("destination", destination)
+
+	if err == nil {
+		metrics.SlackPosts.Inc(destination, "success")
+		logger.Tracef("Sent message to Slack in %v", time.Since(start))
+		return
+	}
+
+	metrics.SlackPosts.Inc(destination, "failure")
+	logger.Debugf("Failed to send message to Slack: [%v]", err)
+
+	c.mutex.Lock()
+	c.lastError = err.Error()
+	c.lastErrorAt = time.Now()
+	c.mutex.Unlock()
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"sort"
+)
+
+// PriceSource provides an interface for retrieving the value of single items, used to calculate the value of kills
+type PriceSource interface {
+	// ItemPrice returns the price of a single item of the given type, returning false if no price is known
+	ItemPrice(typeID int64) (float64, bool)
+}
+
+// ZKillboardValueBreakdown represents the value of a kill split up into the ship, dropped, destroyed and fitted items
+type ZKillboardValueBreakdown struct {
+	Ship      float64
+	Dropped   float64
+	Destroyed float64
+	Fitted    float64
+	Total     float64
+}
+
+// ZKillboardItemValue represents the aggregated quantity and value of all items of a type on a kill
+type ZKillboardItemValue struct {
+	TypeID   int64
+	Quantity int64
+	Value    float64
+}
+
+// IsFittedFlag checks whether the given inventory flag represents a fitted slot (low, medium, high, rig, subsystem or service slots)
+func IsFittedFlag(flag int64) bool {
+	return (flag >= 11 && flag <= 34) || (flag >= 92 && flag <= 99) || (flag >= 125 && flag <= 132) || (flag >= 164 && flag <= 171)
+}
+
+// ValueBreakdown calculates the value of the entry split up into dropped, destroyed and fitted items.
+// Items without a known price are valued using their stored value. If no price source is given or the ship or any item remains unpriced, the values provided by zKillboard are used, fitted values only being available for the current zKillboard format
+func (e ZKillboardEntry) ValueBreakdown(prices PriceSource) ZKillboardValueBreakdown {
+	if prices == nil {
+		return e.zKillboardValueBreakdown()
+	}
+
+	var breakdown ZKillboardValueBreakdown
+	var ok bool
+
+	breakdown.Ship, ok = prices.ItemPrice(e.Victim.ShipTypeID)
+	if !ok {
+		return e.zKillboardValueBreakdown()
+	}
+
+	for _, item := range e.Items {
+		price, ok := itemPrice(prices, item)
+		if !ok {
+			return e.zKillboardValueBreakdown()
+		}
+
+		breakdown.Dropped += price * float64(item.QuantityDropped)
+		breakdown.Destroyed += price * float64(item.QuantityDestroyed)
+
+		if IsFittedFlag(item.Flag) {
+			breakdown.Fitted += price * float64(item.QuantityDropped+item.QuantityDestroyed)
+		}
+	}
+
+	breakdown.Total = breakdown.Ship + breakdown.Dropped + breakdown.Destroyed
+
+	return breakdown
+}
+
+// itemPrice returns the price of a single unit of the given item, falling back to the value stored with the item if the price source knows no price
+func itemPrice(prices PriceSource, item ZKillboardItem) (float64, bool) {
+	price, ok := prices.ItemPrice(item.TypeID)
+	if ok {
+		return price, true
+	}
+
+	quantity := item.QuantityDropped + item.QuantityDestroyed
+	if item.Value > 0 && quantity > 0 {
+		return item.Value / float64(quantity), true
+	}
+
+	return 0, false
+}
+
+// zKillboardValueBreakdown returns the value breakdown of the entry as provided by zKillboard
+func (e ZKillboardEntry) zKillboardValueBreakdown() ZKillboardValueBreakdown {
+	return ZKillboardValueBreakdown{
+		Ship:      e.Misc.TotalValue - e.Misc.DroppedValue - e.Misc.DestroyedValue,
+		Dropped:   e.Misc.DroppedValue,
+		Destroyed: e.Misc.DestroyedValue,
+		Fitted:    e.Misc.FittedValue,
+		Total:     e.Misc.TotalValue,
+	}
+}
+
+// Value returns the total value of the entry calculated using the given price source, falling back to the value provided by zKillboard if no price source is given or not all items of the entry are priced
+func (e ZKillboardEntry) Value(prices PriceSource) float64 {
+	return e.ValueBreakdown(prices).Total
+}
+
+// ApplyItemValues sets the value of each item of the entry to the combined price of its dropped and destroyed quantity, leaving items without a known price at zero
+func (e *ZKillboardEntry) ApplyItemValues(prices PriceSource) {
+	if prices == nil {
+		return
+	}
+
+	for i := range e.Items {
+		price, _ := prices.ItemPrice(e.Items[i].TypeID)
+		e.Items[i].Value = price * float64(e.Items[i].QuantityDropped+e.Items[i].QuantityDestroyed)
+	}
+}
+
+// FittedModules returns the aggregated fitted modules of the entry, listing up to limit types. Modules are valued using the given price source or the values stored with the items if none is given.
+// If any module has a known value, the modules are ordered by descending value and true is returned, otherwise they remain in slot order
+func (e ZKillboardEntry) FittedModules(prices PriceSource, limit int) ([]ZKillboardItemValue, bool) {
+	modules := make(map[int64]*ZKillboardItemValue)
+	order := make([]int64, 0)
+
+	for _, item := range e.Items {
+		if !IsFittedFlag(item.Flag) {
+			continue
+		}
+
+		module, ok := modules[item.TypeID]
+		if !ok {
+			module = &ZKillboardItemValue{
+				TypeID: item.TypeID,
+			}
+			modules[item.TypeID] = module
+			order = append(order, item.TypeID)
+		}
+
+		module.Quantity += item.QuantityDropped + item.QuantityDestroyed
+		module.Value += itemValue(prices, item)
+	}
+
+	values := make([]ZKillboardItemValue, 0, len(order))
+	ranked := false
+
+	for _, typeID := range order {
+		values = append(values, *modules[typeID])
+
+		if modules[typeID].Value > 0 {
+			ranked = true
+		}
+	}
+
+	if ranked {
+		sort.Stable(ByItemValue(values))
+	}
+
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+
+	return values, ranked
+}
+
+// itemValue returns the combined value of the dropped and destroyed quantity of the given item, using the price source if given and the value stored with the item otherwise
+func itemValue(prices PriceSource, item ZKillboardItem) float64 {
+	if prices == nil {
+		return item.Value
+	}
+
+	price, _ := itemPrice(prices, item)
+
+	return price * float64(item.QuantityDropped+item.QuantityDestroyed)
+}
+
+// ByItemValue represents an array of item values, used for sorting by descending value
+type ByItemValue []ZKillboardItemValue
+
+// Len returns the length of the array of item values to sort
+func (v ByItemValue) Len() int {
+	return len(v)
+}
+
+// Swap swaps two entries in the array of item values to sort
+func (v ByItemValue) Swap(i, j int) {
+	v[i], v[j] = v[j], v[i]
+}
+
+// Less is used for sorting the array of item values by descending value
+func (v ByItemValue) Less(i, j int) bool {
+	return v[i].Value > v[j].Value
+}
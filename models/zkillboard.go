@@ -1,6 +1,20 @@
 package models
 
-// ZKillboardEntry represents a kill or loss entry received via the zKillboard API
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+const (
+	// ZKillboardTimeFormat represents the time format used by the zKillboard API for kill times
+	ZKillboardTimeFormat = "2006-01-02 15:04:05"
+	// ESITimeFormat represents the time format used by the EVE Swagger Interface for kill times
+	ESITimeFormat = "2006-01-02T15:04:05Z"
+)
+
+// ZKillboardEntry represents a kill or loss entry received via the zKillboard API.
+// Both the legacy format and the current format (based on ESI killmails) are accepted, the latter being converted to the legacy fields while decoding
 type ZKillboardEntry struct {
 	KillID        int64                   `json:"killID"`
 	SolarSystemID int64                   `json:"solarSystemID"`
@@ -45,18 +59,219 @@ type ZKillboardAttacker struct {
 
 // ZKillboardItem represents an item of a kill as received via the zKillboard API
 type ZKillboardItem struct {
-	TypeID            int64 `json:"typeID"`
-	Flag              int64 `json:"flag"`
-	QuantityDropped   int64 `json:"qntDropped"`
-	QuantityDestroyed int64 `json:"qntDestroyed"`
-	Singleton         int64 `json:"singleton"`
+	TypeID            int64   `json:"typeID"`
+	Flag              int64   `json:"flag"`
+	QuantityDropped   int64   `json:"qntDropped"`
+	QuantityDestroyed int64   `json:"qntDestroyed"`
+	Singleton         int64   `json:"singleton"`
+	Value             float64 `json:"value,omitempty"`
 }
 
 // ZKillboardMiscellaneous represents miscellaneous information about a kill as received via the zKillboard API
 type ZKillboardMiscellaneous struct {
-	Hash       string  `json:"hash"`
-	TotalValue float64 `json:"totalValue"`
-	Points     int64   `json:"points"`
+	LocationID     int64   `json:"locationID"`
+	Hash           string  `json:"hash"`
+	FittedValue    float64 `json:"fittedValue"`
+	DroppedValue   float64 `json:"droppedValue"`
+	DestroyedValue float64 `json:"destroyedValue"`
+	TotalValue     float64 `json:"totalValue"`
+	Points         int64   `json:"points"`
+	NPC            bool    `json:"npc"`
+	Solo           bool    `json:"solo"`
+	Awox           bool    `json:"awox"`
+}
+
+// zKillboardLegacyEntry is used to decode entries in the legacy format without recursively calling the custom decoder
+type zKillboardLegacyEntry ZKillboardEntry
+
+// zKillboardCurrentEntry represents a kill or loss entry in the current zKillboard format, optionally including the ESI killmail
+type zKillboardCurrentEntry struct {
+	ESIKillmail
+	Misc ZKillboardMiscellaneous `json:"zkb"`
+}
+
+// UnmarshalJSON decodes a kill or loss entry in either the legacy or the current zKillboard format
+func (e *ZKillboardEntry) UnmarshalJSON(data []byte) error {
+	var format struct {
+		KillmailID int64 `json:"killmail_id"`
+	}
+
+	err := json.Unmarshal(data, &format)
+	if err != nil {
+		return err
+	}
+
+	if format.KillmailID == 0 {
+		var legacy zKillboardLegacyEntry
+
+		err = json.Unmarshal(data, &legacy)
+		if err != nil {
+			return err
+		}
+
+		*e = ZKillboardEntry(legacy)
+
+		return nil
+	}
+
+	var current zKillboardCurrentEntry
+
+	err = json.Unmarshal(data, &current)
+	if err != nil {
+		return err
+	}
+
+	*e = ZKillboardEntry{
+		KillID: current.KillmailID,
+		Misc:   current.Misc,
+	}
+
+	if len(current.KillmailTime) > 0 {
+		return e.ApplyESIKillmail(&current.ESIKillmail)
+	}
+
+	return nil
+}
+
+// HasKillmail checks whether the entry contains the full killmail or if only the zKillboard information is available
+func (e ZKillboardEntry) HasKillmail() bool {
+	return len(e.KillTime) > 0
+}
+
+// ApplyESIKillmail fills the entry with the information provided by the given ESI killmail, returning an error if the kill time could not be parsed
+func (e *ZKillboardEntry) ApplyESIKillmail(killmail *ESIKillmail) error {
+	killTime, err := time.Parse(ESITimeFormat, killmail.KillmailTime)
+	if err != nil {
+		return err
+	}
+
+	e.KillID = killmail.KillmailID
+	e.SolarSystemID = killmail.SolarSystemID
+	e.MoonID = killmail.MoonID
+	e.KillTime = killTime.UTC().Format(ZKillboardTimeFormat)
+
+	e.Victim = ZKillboardVictim{
+		CharacterID:   killmail.Victim.CharacterID,
+		CorporationID: killmail.Victim.CorporationID,
+		AllianceID:    killmail.Victim.AllianceID,
+		FactionID:     killmail.Victim.FactionID,
+		ShipTypeID:    killmail.Victim.ShipTypeID,
+		DamageTaken:   killmail.Victim.DamageTaken,
+	}
+
+	e.Attackers = make([]ZKillboardAttacker, 0, len(killmail.Attackers))
+	for _, attacker := range killmail.Attackers {
+		finalBlow := int64(0)
+		if attacker.FinalBlow {
+			finalBlow = 1
+		}
+
+		e.Attackers = append(e.Attackers, ZKillboardAttacker{
+			CharacterID:    attacker.CharacterID,
+			CorporationID:  attacker.CorporationID,
+			AllianceID:     attacker.AllianceID,
+			FactionID:      attacker.FactionID,
+			ShipTypeID:     attacker.ShipTypeID,
+			WeaponTypeID:   attacker.WeaponTypeID,
+			DamageDone:     attacker.DamageDone,
+			FinalBlow:      finalBlow,
+			SecurityStatus: attacker.SecurityStatus,
+		})
+	}
+
+	e.Items = appendESIItems(make([]ZKillboardItem, 0, len(killmail.Victim.Items)), killmail.Victim.Items)
+
+	return nil
+}
+
+// appendESIItems converts the given ESI killmail items to zKillboard items, flattening the contents of containers
+func appendESIItems(items []ZKillboardItem, esiItems []ESIKillmailItem) []ZKillboardItem {
+	for _, item := range esiItems {
+		items = append(items, ZKillboardItem{
+			TypeID:            item.ItemTypeID,
+			Flag:              item.Flag,
+			QuantityDropped:   item.QuantityDropped,
+			QuantityDestroyed: item.QuantityDestroyed,
+			Singleton:         item.Singleton,
+		})
+
+		items = appendESIItems(items, item.Items)
+	}
+
+	return items
+}
+
+// ZKillboardEntryType represents the type of a killboard entry as seen from the tracked corporation
+type ZKillboardEntryType int
+
+const (
+	// ZKillboardEntryTypeKill represents a kill scored by the tracked corporation
+	ZKillboardEntryTypeKill ZKillboardEntryType = iota
+	// ZKillboardEntryTypeLoss represents a loss suffered by the tracked corporation
+	ZKillboardEntryTypeLoss
+)
+
+// String returns a easily readable string representations of the given ZKillboardEntryType
+func (t ZKillboardEntryType) String() string {
+	switch t {
+	case ZKillboardEntryTypeKill:
+		return "kill"
+	case ZKillboardEntryTypeLoss:
+		return "loss"
+	default:
+		return "unknown"
+	}
+}
+
+// ZKillboardTimelineEntry represents a kill or loss as part of the chronologically ordered stream of a corporation
+type ZKillboardTimelineEntry struct {
+	Type  ZKillboardEntryType
+	Entry ZKillboardEntry
+}
+
+// ParseKillTime parses the kill time of the entry, returning an error if the time format is invalid
+func (e ZKillboardEntry) ParseKillTime() (time.Time, error) {
+	return time.Parse(ZKillboardTimeFormat, e.KillTime)
+}
+
+// TypeIDs returns the IDs of all item types referenced by the entry, including ships, weapons and items
+func (e ZKillboardEntry) TypeIDs() []int64 {
+	typeIDs := make([]int64, 0, 1+len(e.Attackers)*2+len(e.Items))
+
+	typeIDs = append(typeIDs, e.Victim.ShipTypeID)
+
+	for _, attacker := range e.Attackers {
+		typeIDs = append(typeIDs, attacker.ShipTypeID, attacker.WeaponTypeID)
+	}
+
+	for _, item := range e.Items {
+		typeIDs = append(typeIDs, item.TypeID)
+	}
+
+	return typeIDs
+}
+
+// MergeTimeline merges the given kills and losses into a single stream, ordered by kill time and kill ID
+func MergeTimeline(kills []ZKillboardEntry, losses []ZKillboardEntry) []ZKillboardTimelineEntry {
+	timeline := make([]ZKillboardTimelineEntry, 0, len(kills)+len(losses))
+
+	for _, kill := range kills {
+		timeline = append(timeline, ZKillboardTimelineEntry{
+			Type:  ZKillboardEntryTypeKill,
+			Entry: kill,
+		})
+	}
+
+	for _, loss := range losses {
+		timeline = append(timeline, ZKillboardTimelineEntry{
+			Type:  ZKillboardEntryTypeLoss,
+			Entry: loss,
+		})
+	}
+
+	sort.Stable(ByKillTime(timeline))
+
+	return timeline
 }
 
 // ByKillID represents an array of kills or losses, used for sorting by kill ID
@@ -76,3 +291,32 @@ func (k ByKillID) Swap(i, j int) {
 func (k ByKillID) Less(i, j int) bool {
 	return k[i].KillID < k[j].KillID
 }
+
+// ByKillTime represents an array of timeline entries, used for sorting by kill time and kill ID
+type ByKillTime []ZKillboardTimelineEntry
+
+// Len returns the length of the array of timeline entries to sort
+func (k ByKillTime) Len() int {
+	return len(k)
+}
+
+// Swap swaps two entries in the array of timeline entries to sort
+func (k ByKillTime) Swap(i, j int) {
+	k[i], k[j] = k[j], k[i]
+}
+
+// Less is used for sorting the array of timeline entries by comparing kill times, falling back to kill IDs and entry types for identical times
+func (k ByKillTime) Less(i, j int) bool {
+	timeI, errI := k[i].Entry.ParseKillTime()
+	timeJ, errJ := k[j].Entry.ParseKillTime()
+
+	if errI == nil && errJ == nil && !timeI.Equal(timeJ) {
+		return timeI.Before(timeJ)
+	}
+
+	if k[i].Entry.KillID != k[j].Entry.KillID {
+		return k[i].Entry.KillID < k[j].Entry.KillID
+	}
+
+	return k[i].Type < k[j].Type
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const (
+	testLegacyEntry = `{
+		"killID": 56544012,
+		"solarSystemID": 30002187,
+		"moonID": 0,
+		"killTime": "2016-08-21 18:11:23",
+		"victim": {"characterID": 95465499, "characterName": "Victim", "corporationID": 98388312, "shipTypeID": 587, "damageTaken": 1234},
+		"attackers": [{"characterID": 90000001, "corporationID": 98000001, "shipTypeID": 17738, "weaponTypeID": 2929, "damageDone": 1234, "finalBlow": 1}],
+		"items": [{"typeID": 2410, "flag": 27, "qntDropped": 1, "qntDestroyed": 0, "singleton": 0}],
+		"zkb": {"locationID": 40139328, "hash": "abcdef", "totalValue": 350000, "points": 1}
+	}`
+	testCurrentEntry = `{
+		"killmail_id": 72409880,
+		"killmail_time": "2018-09-06T20:09:30Z",
+		"solar_system_id": 30000142,
+		"victim": {
+			"character_id": 95465499,
+			"corporation_id": 98388312,
+			"ship_type_id": 587,
+			"damage_taken": 1234,
+			"items": [
+				{"item_type_id": 2410, "flag": 27, "quantity_dropped": 1, "singleton": 0},
+				{"item_type_id": 3467, "flag": 5, "quantity_destroyed": 2, "singleton": 0, "items": [{"item_type_id": 34, "flag": 5, "quantity_dropped": 100, "singleton": 0}]}
+			]
+		},
+		"attackers": [
+			{"character_id": 90000001, "corporation_id": 98000001, "ship_type_id": 17738, "weapon_type_id": 2929, "damage_done": 1000, "final_blow": false},
+			{"character_id": 90000002, "corporation_id": 98000001, "ship_type_id": 17738, "weapon_type_id": 2929, "damage_done": 234, "final_blow": true}
+		],
+		"zkb": {"locationID": 60003760, "hash": "123456", "totalValue": 1500000, "points": 1}
+	}`
+	testHashOnlyEntry = `{"killmail_id": 72409881, "zkb": {"locationID": 60003760, "hash": "789abc", "totalValue": 900000}}`
+)
+
+func TestZKillboardEntryUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		killID        int64
+		solarSystemID int64
+		killTime      string
+		hash          string
+		shipTypeID    int64
+		attackers     int
+		finalBlow     int
+		items         int
+		hasKillmail   bool
+	}{
+		{
+			name:          "legacy format",
+			data:          testLegacyEntry,
+			killID:        56544012,
+			solarSystemID: 30002187,
+			killTime:      "2016-08-21 18:11:23",
+			hash:          "abcdef",
+			shipTypeID:    587,
+			attackers:     1,
+			finalBlow:     0,
+			items:         1,
+			hasKillmail:   true,
+		},
+		{
+			name:          "current format with killmail",
+			data:          testCurrentEntry,
+			killID:        72409880,
+			solarSystemID: 30000142,
+			killTime:      "2018-09-06 20:09:30",
+			hash:          "123456",
+			shipTypeID:    587,
+			attackers:     2,
+			finalBlow:     1,
+			items:         3,
+			hasKillmail:   true,
+		},
+		{
+			name:        "current format without killmail",
+			data:        testHashOnlyEntry,
+			killID:      72409881,
+			hash:        "789abc",
+			hasKillmail: false,
+		},
+	}
+
+	for _, test := range tests {
+		var entry ZKillboardEntry
+
+		err := json.Unmarshal([]byte(test.data), &entry)
+		if err != nil {
+			t.Errorf("%s: failed to decode entry: %v", test.name, err)
+			continue
+		}
+
+		if entry.KillID != test.killID {
+			t.Errorf("%s: expected kill ID %d, got %d", test.name, test.killID, entry.KillID)
+		}
+		if entry.SolarSystemID != test.solarSystemID {
+			t.Errorf("%s: expected solar system ID %d, got %d", test.name, test.solarSystemID, entry.SolarSystemID)
+		}
+		if entry.KillTime != test.killTime {
+			t.Errorf("%s: expected kill time %q, got %q", test.name, test.killTime, entry.KillTime)
+		}
+		if entry.Misc.Hash != test.hash {
+			t.Errorf("%s: expected hash %q, got %q", test.name, test.hash, entry.Misc.Hash)
+		}
+		if entry.Victim.ShipTypeID != test.shipTypeID {
+			t.Errorf("%s: expected victim ship type ID %d, got %d", test.name, test.shipTypeID, entry.Victim.ShipTypeID)
+		}
+		if len(entry.Attackers) != test.attackers {
+			t.Errorf("%s: expected %d attackers, got %d", test.name, test.attackers, len(entry.Attackers))
+		} else if test.attackers > 0 && entry.Attackers[test.finalBlow].FinalBlow != 1 {
+			t.Errorf("%s: expected attacker #%d to have the final blow", test.name, test.finalBlow)
+		}
+		if len(entry.Items) != test.items {
+			t.Errorf("%s: expected %d items, got %d", test.name, test.items, len(entry.Items))
+		}
+		if entry.HasKillmail() != test.hasKillmail {
+			t.Errorf("%s: expected HasKillmail to return %v", test.name, test.hasKillmail)
+		}
+	}
+}
+
+func TestZKillboardEntryUnmarshalJSONInvalid(t *testing.T) {
+	tests := []string{
+		`[]`,
+		`{"killmail_id": "abc"}`,
+		`{"killmail_id": 1, "killmail_time": "yesterday"}`,
+	}
+
+	for _, data := range tests {
+		var entry ZKillboardEntry
+
+		err := json.Unmarshal([]byte(data), &entry)
+		if err == nil {
+			t.Errorf("Expected decoding %s to fail", data)
+		}
+	}
+}
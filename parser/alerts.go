@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// CheckLossAlert checks whether the given loss exceeds any of the alert thresholds set for the corporation, returning the reason for the alert or an empty string if none should be sent
+func (parser *Parser) CheckLossAlert(corporation *models.Corporation, item models.ZKillboardTimelineEntry) (string, error) {
+	if item.Type != models.ZKillboardEntryTypeLoss {
+		return "", nil
+	}
+
+	logger := parser.logger(corporation).WithField("killID", item.Entry.KillID)
+
+	if corporation.AlertValue > 0 && item.Entry.Value(parser.prices) >= corporation.AlertValue {
+		return fmt.Sprintf("value exceeds %s ISK", humanize.Commaf(corporation.AlertValue)), nil
+	}
+
+	for _, groupID := range corporation.AlertShipGroups {
+		group, err := parser.lookup.FetchItemGroup(logger, groupID)
+		if err != nil {
+			return "", err
+		}
+
+		if groupContainsType(group, item.Entry.Victim.ShipTypeID) {
+			return fmt.Sprintf("%s lost", group.Name), nil
+		}
+	}
+
+	if corporation.AlertStructures {
+		structure, err := parser.IsStructure(logger, item.Entry.Victim.ShipTypeID)
+		if err != nil {
+			return "", err
+		}
+
+		if structure {
+			return "structure lost", nil
+		}
+	}
+
+	return "", nil
+}
+
+// IsStructure checks whether the group of the given item type belongs to any of the structure categories, logging lookups using the given logger
+func (parser *Parser) IsStructure(logger *misc.Log, typeID int64) (bool, error) {
+	itemType, err := parser.lookup.FetchItemType(logger, typeID)
+	if err != nil {
+		return false, err
+	}
+
+	if itemType.Group.ID == 0 {
+		return false, fmt.Errorf("Unknown item group for type #%d", typeID)
+	}
+
+	group, err := parser.lookup.FetchItemGroup(logger, itemType.Group.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, categoryID := range models.StructureCategoryIDs {
+		if group.Category.ID == categoryID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// SendLossAlert prepares a high-visibility payload mentioning the configured Slack users for the given loss and sends it to the Slack webhook
+func (parser *Parser) SendLossAlert(corporation *models.Corporation, entry models.ZKillboardEntry, reason string) error {
+	var payload models.SlackPayload
+	var alert models.SlackAttachment
+
+	logger := parser.logger(corporation).WithField("killID", entry.KillID)
+
+	parser.FillNames(&entry)
+
+	victimShipName := fmt.Sprintf("#%d", entry.Victim.ShipTypeID)
+	itemType, err := parser.lookup.FetchItemType(logger, entry.Victim.ShipTypeID)
+	if err != nil {
+		logger.Warnf("Failed to query ship type ID #%d for victim of loss alert #%d", entry.Victim.ShipTypeID, entry.KillID)
+	} else {
+		victimShipName = itemType.Name
+	}
+
+	victimName := entry.Victim.CharacterName
+	if entry.Victim.CharacterID == 0 {
+		victimName = victimShipName
+	}
+
+	solarSystemName := fmt.Sprintf("#%d", entry.SolarSystemID)
+	locationInfo, err := parser.lookup.FetchLocationInfo(logger, entry.SolarSystemID)
+	if err != nil {
+		logger.Warnf("Failed to query location info for solar system ID #%d of loss alert #%d", entry.SolarSystemID, entry.KillID)
+	} else {
+		solarSystemName = locationInfo.SolarSystemName
+	}
+
+	killLink := fmt.Sprintf("https://zkillboard.com/kill/%d/", entry.KillID)
+
+	payload.Text = fmt.Sprintf("%s %s lost a %s worth %s ISK in %s (%s)", AlertMention(corporation), CorporationName(corporation), victimShipName, humanize.Commaf(entry.Value(parser.prices)), solarSystemName, reason)
+
+	alert.Color = "danger"
+	alert.Fallback = fmt.Sprintf("%s lost a %s worth %s ISK", victimName, victimShipName, humanize.Commaf(entry.Value(parser.prices)))
+	alert.Title = alert.Fallback
+	alert.TitleLink = killLink
+	alert.ThumbURL = fmt.Sprintf("https://imageserver.eveonline.com/render/%d_64.png", entry.Victim.ShipTypeID)
+
+	payload.Attachments = append(payload.Attachments, alert)
+
+	_, err = parser.slackClient.PostPayload(&payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// sendLossAlert checks the given entry against the alert thresholds of the corporation and sends a loss alert if any was exceeded, logging encountered errors.
+// Returns whether an alert was sent
+func (parser *Parser) sendLossAlert(corporation *models.Corporation, item models.ZKillboardTimelineEntry) bool {
+	logger := parser.logger(corporation).WithField("killID", item.Entry.KillID)
+
+	reason, err := parser.CheckLossAlert(corporation, item)
+	if err != nil {
+		logger.Warnf("Failed to check alert thresholds for %s #%d: [%v]", item.Type, item.Entry.KillID, err)
+		return false
+	} else if len(reason) == 0 {
+		return false
+	}
+
+	logger.Debugf("Sending alert for %s #%d: %s", item.Type, item.Entry.KillID, reason)
+
+	err = parser.SendLossAlert(corporation, item.Entry, reason)
+	if err != nil {
+		logger.Warnf("Failed to send alert for %s #%d: [%v]", item.Type, item.Entry.KillID, err)
+		return false
+	}
+
+	// Wait in order to abide to Slack's message limit
+	time.Sleep(time.Second * 1)
+
+	return true
+}
+
+// AlertMention returns the Slack mention used for loss alerts of the given corporation, defaulting to the whole channel
+func AlertMention(corporation *models.Corporation) string {
+	mention := strings.TrimSpace(corporation.AlertMention)
+
+	if len(mention) == 0 {
+		return "<!channel>"
+	}
+
+	if !strings.HasPrefix(mention, "<") {
+		return fmt.Sprintf("<!%s>", mention)
+	}
+
+	return mention
+}
+
+// groupContainsType checks whether the given type ID is part of the item group
+func groupContainsType(group *models.CRESTItemGroup, typeID int64) bool {
+	for _, itemType := range group.Types {
+		if itemType.ID == typeID {
+			return true
+		}
+	}
+
+	return false
+}
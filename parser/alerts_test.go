@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/morpheusxaut/eveslackkills/lookup"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// fakeLookup resolves item types and groups from fixed maps, all other methods of the client are left unimplemented
+type fakeLookup struct {
+	lookup.Client
+
+	itemTypes  map[int64]*models.CRESTItemType
+	itemGroups map[int64]*models.CRESTItemGroup
+}
+
+// FetchItemType returns the configured item type or an error if it is unknown
+func (l *fakeLookup) FetchItemType(log *misc.Log, typeID int64) (*models.CRESTItemType, error) {
+	itemType, ok := l.itemTypes[typeID]
+	if !ok {
+		return nil, fmt.Errorf("Unknown item type #%d", typeID)
+	}
+
+	return itemType, nil
+}
+
+// FetchItemGroup returns the configured item group or an error if it is unknown
+func (l *fakeLookup) FetchItemGroup(log *misc.Log, groupID int64) (*models.CRESTItemGroup, error) {
+	group, ok := l.itemGroups[groupID]
+	if !ok {
+		return nil, fmt.Errorf("Unknown item group #%d", groupID)
+	}
+
+	return group, nil
+}
+
+func TestIsStructure(t *testing.T) {
+	parser := &Parser{
+		lookup: &fakeLookup{
+			itemTypes: map[int64]*models.CRESTItemType{
+				35832: {Name: "Astrahus", Group: models.CRESTReference{ID: 1657}},
+				12235: {Name: "Amarr Control Tower", Group: models.CRESTReference{ID: 365}},
+				587:   {Name: "Rifter", Group: models.CRESTReference{ID: 25}},
+				670:   {Name: "Capsule"},
+				1:     {Name: "Broken Group", Group: models.CRESTReference{ID: 999}},
+			},
+			itemGroups: map[int64]*models.CRESTItemGroup{
+				1657: {Name: "Citadel", Category: models.CRESTReference{ID: 65}},
+				365:  {Name: "Control Tower", Category: models.CRESTReference{ID: 23}},
+				25:   {Name: "Frigate", Category: models.CRESTReference{ID: 6}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		typeID    int64
+		structure bool
+		err       bool
+	}{
+		{name: "citadel", typeID: 35832, structure: true},
+		{name: "control tower", typeID: 12235, structure: true},
+		{name: "frigate", typeID: 587, structure: false},
+		{name: "type without group", typeID: 670, err: true},
+		{name: "unknown type", typeID: 2, err: true},
+		{name: "unknown group", typeID: 1, err: true},
+	}
+
+	for _, test := range tests {
+		structure, err := parser.IsStructure(misc.Logger, test.typeID)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: expected error, got none", test.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+
+		if structure != test.structure {
+			t.Errorf("%s: expected structure %v, got %v", test.name, test.structure, structure)
+		}
+	}
+}
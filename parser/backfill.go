@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// Backfill imports the kills and losses of the corporation within the given period into the killmail history without posting them to Slack.
+// The checkpoints of the corporation are advanced to its newest kill and loss on zKillboard regardless of the period, returning the number of imported kills and losses.
+// Nothing is imported if a killmail could not be retrieved, allowing the backfill to be run again later
+func (parser *Parser) Backfill(corporation *models.Corporation, from time.Time, to time.Time) (int, int, error) {
+	latestKillID, err := parser.zkillboard.FetchLatestKillID(corporation.EVECorporationID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	latestLossID, err := parser.zkillboard.FetchLatestLossID(corporation.EVECorporationID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	kills, err := parser.zkillboard.FetchKillsBetween(corporation.EVECorporationID, from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	kills, stoppedAt := parser.CompleteEntries(corporation, kills)
+	if stoppedAt > 0 {
+		return 0, 0, fmt.Errorf("Failed to retrieve killmail #%d, run the backfill again to import the remaining kills and losses", stoppedAt)
+	}
+
+	parser.logger(corporation).Tracef("Fetched %d kills for backfill of corporation #%d", len(kills), corporation.EVECorporationID)
+
+	losses, err := parser.zkillboard.FetchLossesBetween(corporation.EVECorporationID, from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	losses, stoppedAt = parser.CompleteEntries(corporation, losses)
+	if stoppedAt > 0 {
+		return 0, 0, fmt.Errorf("Failed to retrieve killmail #%d, run the backfill again to import the remaining kills and losses", stoppedAt)
+	}
+
+	parser.logger(corporation).Tracef("Fetched %d losses for backfill of corporation #%d", len(losses), corporation.EVECorporationID)
+
+	var importedKills int
+	var importedLosses int
+
+	for _, item := range models.MergeTimeline(kills, losses) {
+		err = parser.StoreKillmail(corporation, item)
+		if err != nil {
+			parser.logger(corporation).WithField("killID", item.Entry.KillID).Warnf("Failed to store %s #%d in killmail history: [%v]", item.Type, item.Entry.KillID, err)
+			continue
+		}
+
+		if item.Type == models.ZKillboardEntryTypeKill {
+			importedKills++
+		} else {
+			importedLosses++
+		}
+
+		parser.UpdateCheckpoint(corporation, item)
+	}
+
+	// Skip everything posted until now, even outside of the imported period, so the next update does not post old entries
+	if latestKillID > corporation.LastKillID {
+		corporation.LastKillID = latestKillID
+	}
+	if latestLossID > corporation.LastLossID {
+		corporation.LastLossID = latestLossID
+	}
+
+	err = parser.database.SaveCorporationCheckpoints(parser.logger(corporation), corporation)
+	if err != nil {
+		return importedKills, importedLosses, err
+	}
+
+	return importedKills, importedLosses, nil
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// SendBacklogSummary prepares a payload summarising a large backlog of kills and losses found while catching up after downtime and sends it to Slack
+func (parser *Parser) SendBacklogSummary(corporation *models.Corporation, entries []models.ZKillboardTimelineEntry) error {
+	var payload models.SlackPayload
+	var summary models.SlackAttachment
+
+	var kills int
+	var losses int
+	var iskDestroyed float64
+	var iskLost float64
+
+	var mostExpensiveKill *models.ZKillboardEntry
+	var mostExpensiveKillValue float64
+	var mostExpensiveLoss *models.ZKillboardEntry
+	var mostExpensiveLossValue float64
+
+	for i := range entries {
+		entry := &entries[i].Entry
+		value := entry.Value(parser.prices)
+
+		switch entries[i].Type {
+		case models.ZKillboardEntryTypeKill:
+			kills++
+			iskDestroyed += value
+
+			if mostExpensiveKill == nil || value > mostExpensiveKillValue {
+				mostExpensiveKill = entry
+				mostExpensiveKillValue = value
+			}
+		case models.ZKillboardEntryTypeLoss:
+			losses++
+			iskLost += value
+
+			if mostExpensiveLoss == nil || value > mostExpensiveLossValue {
+				mostExpensiveLoss = entry
+				mostExpensiveLossValue = value
+			}
+		}
+	}
+
+	title := fmt.Sprintf("Caught up on %d kills and %d losses for %s", kills, losses, CorporationName(corporation))
+
+	if iskDestroyed >= iskLost {
+		summary.Color = "good"
+	} else {
+		summary.Color = "danger"
+	}
+
+	summary.Fallback = title
+	summary.Title = title
+	summary.TitleLink = fmt.Sprintf("https://zkillboard.com/corporation/%d/", corporation.EVECorporationID)
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "ISK destroyed",
+		Value: fmt.Sprintf("%s ISK", humanize.Commaf(iskDestroyed)),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "ISK lost",
+		Value: fmt.Sprintf("%s ISK", humanize.Commaf(iskLost)),
+		Short: true,
+	})
+
+	if mostExpensiveKill != nil {
+		summary.Fields = append(summary.Fields, models.SlackField{
+			Title: "Most expensive kill",
+			Value: parser.backlogEntryLink(corporation, mostExpensiveKill, mostExpensiveKillValue),
+			Short: true,
+		})
+	}
+
+	if mostExpensiveLoss != nil {
+		summary.Fields = append(summary.Fields, models.SlackField{
+			Title: "Most expensive loss",
+			Value: parser.backlogEntryLink(corporation, mostExpensiveLoss, mostExpensiveLossValue),
+			Short: true,
+		})
+	}
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "Period",
+		Value: fmt.Sprintf("%s - %s", entries[0].Entry.KillTime, entries[len(entries)-1].Entry.KillTime),
+		Short: false,
+	})
+
+	payload.Attachments = append(payload.Attachments, summary)
+
+	_, err := parser.slackClient.PostPayload(&payload)
+	return err
+}
+
+// backlogEntryLink formats a link to the given entry of the corporation including the name of the destroyed ship and its value
+func (parser *Parser) backlogEntryLink(corporation *models.Corporation, entry *models.ZKillboardEntry, value float64) string {
+	logger := parser.logger(corporation).WithField("killID", entry.KillID)
+	shipName := fmt.Sprintf("#%d", entry.Victim.ShipTypeID)
+
+	itemType, err := parser.lookup.FetchItemType(logger, entry.Victim.ShipTypeID)
+	if err != nil {
+		logger.Warnf("Failed to query ship type ID #%d for killboard entry #%d", entry.Victim.ShipTypeID, entry.KillID)
+	} else {
+		shipName = itemType.Name
+	}
+
+	return fmt.Sprintf("<https://zkillboard.com/kill/%d/|%s> (%s ISK)", entry.KillID, shipName, humanize.Commaf(value))
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/morpheusxaut/eveslackkills/battle"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// SendBattleReport prepares a payload summarising the given battle and sends it to Slack, updating a previously posted report if possible
+func (parser *Parser) SendBattleReport(corporation *models.Corporation, report *battle.Battle) error {
+	var payload models.SlackPayload
+	var summary models.SlackAttachment
+
+	locationInfo, err := parser.lookup.FetchLocationInfo(parser.logger(corporation), report.SolarSystemID)
+	if err != nil {
+		parser.logger(corporation).Warnf("Failed to query location info for solar system ID #%d of battle report", report.SolarSystemID)
+		return err
+	}
+
+	iskDestroyed := report.ISKDestroyed(parser.prices)
+	iskLost := report.ISKLost(parser.prices)
+	friendlyPilots, hostilePilots := report.Participants(corporation.EVECorporationID)
+
+	title := fmt.Sprintf("Battle in %s: %d ships destroyed, %d ships lost", locationInfo.SolarSystemName, report.ShipsDestroyed(), report.ShipsLost())
+	if report.ReportCount > 0 && !report.Posted() {
+		title = fmt.Sprintf("%s (update #%d)", title, report.ReportCount)
+	}
+
+	if iskDestroyed >= iskLost {
+		summary.Color = "good"
+	} else {
+		summary.Color = "danger"
+	}
+
+	summary.Fallback = title
+	summary.Title = title
+	summary.TitleLink = report.RelatedLink()
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "ISK destroyed",
+		Value: fmt.Sprintf("%s ISK", humanize.Commaf(iskDestroyed)),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "ISK lost",
+		Value: fmt.Sprintf("%s ISK", humanize.Commaf(iskLost)),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "Ships destroyed",
+		Value: humanize.Comma(int64(report.ShipsDestroyed())),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "Ships lost",
+		Value: humanize.Comma(int64(report.ShipsLost())),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "Friendly pilots",
+		Value: humanize.Comma(int64(friendlyPilots)),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "Hostile pilots",
+		Value: humanize.Comma(int64(hostilePilots)),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "Solar system",
+		Value: fmt.Sprintf("<https://zkillboard.com/system/%d|%s> (%.2f) | %s | <https://zkillboard.com/region/%s|%s>", report.SolarSystemID, locationInfo.SolarSystemName, locationInfo.SolarSystemSecurity, locationInfo.ConstellationName, locationInfo.RegionID, locationInfo.RegionName),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "Duration",
+		Value: fmt.Sprintf("%s - %s (%d minutes)", report.StartTime.Format(models.ZKillboardTimeFormat), report.EndTime.Format("15:04:05"), int64(report.Duration().Minutes())),
+		Short: true,
+	})
+
+	payload.Attachments = append(payload.Attachments, summary)
+
+	if report.Posted() {
+		parser.logger(corporation).Tracef("Updating battle report for solar system #%d (message %s)", report.SolarSystemID, report.MessageTimestamp)
+
+		_, err = parser.slackClient.UpdatePayload(report.MessageChannel, report.MessageTimestamp, &payload)
+		if err != nil {
+			return err
+		}
+
+		report.ReportCount++
+
+		return nil
+	}
+
+	resp, err := parser.slackClient.PostPayload(&payload)
+	if err != nil {
+		return err
+	}
+
+	report.MessageChannel = resp.Channel
+	report.MessageTimestamp = resp.Timestamp
+	report.ReportCount++
+
+	parser.recordPost(corporation, 0, "battle", title)
+
+	return nil
+}
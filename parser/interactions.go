@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/morpheusxaut/eveslackkills/lookup"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	// ActionIgnoreSystem represents the button adding the solar system of a kill to the ignored solar systems of the corporation
+	ActionIgnoreSystem = "ignore_system"
+	// ActionShowFitting represents the button listing all modules fitted to the ship of a kill
+	ActionShowFitting = "show_fitting"
+	// ActionShowAttackers represents the button listing all attackers involved in a kill
+	ActionShowAttackers = "show_attackers"
+	// ActionMarkReviewed represents the button flagging a kill as reviewed
+	ActionMarkReviewed = "mark_reviewed"
+
+	// killmailCallbackPrefix represents the prefix of the callback ID attached to kill and loss messages
+	killmailCallbackPrefix = "killmail"
+	// attackerDetailsSize represents the maximum number of attackers listed when showing all attackers of a kill
+	attackerDetailsSize = 30
+)
+
+// killmailActions represents the buttons attached to kill and loss messages if Slack interactivity is enabled
+var killmailActions = []models.SlackAction{
+	{Name: ActionIgnoreSystem, Text: "Ignore this system", Type: "button", Value: ActionIgnoreSystem},
+	{Name: ActionShowFitting, Text: "Show fitting", Type: "button", Value: ActionShowFitting},
+	{Name: ActionShowAttackers, Text: "Show all attackers", Type: "button", Value: ActionShowAttackers},
+	{Name: ActionMarkReviewed, Text: "Mark as reviewed", Type: "button", Value: ActionMarkReviewed, Style: "primary"},
+}
+
+// KillmailCallbackID returns the callback ID attached to the message of the given kill or loss, identifying it once a button was clicked
+func KillmailCallbackID(corporationID int64, killID int64, entryType models.ZKillboardEntryType) string {
+	return fmt.Sprintf("%s:%d:%d:%d", killmailCallbackPrefix, corporationID, killID, int(entryType))
+}
+
+// ParseKillmailCallbackID parses the EVE corporation ID, kill ID and entry type from the given callback ID, returning an error if it is malformed
+func ParseKillmailCallbackID(callbackID string) (int64, int64, models.ZKillboardEntryType, error) {
+	parts := strings.Split(callbackID, ":")
+	if len(parts) != 4 || parts[0] != killmailCallbackPrefix {
+		return 0, 0, 0, fmt.Errorf("Invalid killmail callback ID %q", callbackID)
+	}
+
+	corporationID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Invalid corporation ID in callback ID %q", callbackID)
+	}
+
+	killID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Invalid kill ID in callback ID %q", callbackID)
+	}
+
+	entryType, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Invalid entry type in callback ID %q", callbackID)
+	}
+
+	return corporationID, killID, models.ZKillboardEntryType(entryType), nil
+}
+
+// FittingDetails returns a list of all modules fitted to the ship of the given entry ordered by descending value, keeping the slot order if no values are known
+func (parser *Parser) FittingDetails(entry models.ZKillboardEntry) string {
+	logger := misc.Logger.WithField("killID", entry.KillID)
+
+	itemTypes, err := lookup.ResolveItemTypes(logger, parser.lookup, entry.TypeIDs(), parser.config.LookupConcurrency)
+	if err != nil {
+		logger.Warnf("Failed to resolve all item types of killboard entry #%d: [%v]", entry.KillID, err)
+	}
+
+	return parser.FittingSummary(entry, itemTypes, 0)
+}
+
+// AttackerDetails returns a list of the attackers involved in the given entry ordered by descending damage, including their ship and corporation
+func (parser *Parser) AttackerDetails(entry models.ZKillboardEntry) string {
+	parser.FillNames(&entry)
+
+	logger := misc.Logger.WithField("killID", entry.KillID)
+
+	itemTypes, err := lookup.ResolveItemTypes(logger, parser.lookup, entry.TypeIDs(), parser.config.LookupConcurrency)
+	if err != nil {
+		logger.Warnf("Failed to resolve all item types of killboard entry #%d: [%v]", entry.KillID, err)
+	}
+
+	attackers := make([]models.ZKillboardAttacker, len(entry.Attackers))
+	copy(attackers, entry.Attackers)
+	sort.Stable(byDamageDone(attackers))
+
+	lines := make([]string, 0, len(attackers))
+	for i, attacker := range attackers {
+		if i >= attackerDetailsSize {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(attackers)-attackerDetailsSize))
+			break
+		}
+
+		shipName := fmt.Sprintf("#%d", attacker.ShipTypeID)
+		itemType, ok := itemTypes[attacker.ShipTypeID]
+		if ok {
+			shipName = itemType.Name
+		}
+
+		var name string
+		if attacker.CharacterID != 0 {
+			name = fmt.Sprintf("<https://zkillboard.com/character/%d|%s>", attacker.CharacterID, attacker.CharacterName)
+		} else if len(attacker.FactionName) > 0 {
+			name = attacker.FactionName
+		} else {
+			name = shipName
+		}
+
+		line := fmt.Sprintf("%s (%s) - %s damage", name, shipName, humanize.Comma(attacker.DamageDone))
+		if len(attacker.CorporationName) > 0 {
+			line = fmt.Sprintf("%s | %s", line, attacker.CorporationName)
+		}
+		if attacker.FinalBlow == 1 {
+			line = fmt.Sprintf("%s | final blow", line)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// byDamageDone represents an array of attackers, used for sorting by descending damage
+type byDamageDone []models.ZKillboardAttacker
+
+// Len returns the length of the array of attackers to sort
+func (a byDamageDone) Len() int {
+	return len(a)
+}
+
+// Swap swaps two entries in the array of attackers to sort
+func (a byDamageDone) Swap(i, j int) {
+	a[i], a[j] = a[j], a[i]
+}
+
+// Less is used for sorting the array of attackers by descending damage
+func (a byDamageDone) Less(i, j int) bool {
+	return a[i].DamageDone > a[j].DamageDone
+}
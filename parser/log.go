@@ -0,0 +1,23 @@
+package parser
+
+import (
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// logger returns a logger attaching the current update cycle and the EVE corporation ID of the given corporation to all messages
+func (parser *Parser) logger(corporation *models.Corporation) *misc.Log {
+	parser.stateMutex.RLock()
+	cycleID := parser.cycleID
+	parser.stateMutex.RUnlock()
+
+	fields := misc.Fields{
+		"cycleID": cycleID,
+	}
+
+	if corporation != nil {
+		fields["corporationID"] = corporation.EVECorporationID
+	}
+
+	return misc.Logger.With(fields)
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+	"github.com/morpheusxaut/eveslackkills/reports"
+)
+
+// entityName references the name field belonging to an ID within a killboard entry or statistic
+type entityName struct {
+	id   int64
+	name *string
+}
+
+// FillNames resolves all character, corporation, alliance and faction names missing from the given entry
+func (parser *Parser) FillNames(entry *models.ZKillboardEntry) {
+	entities := []entityName{
+		{entry.Victim.CharacterID, &entry.Victim.CharacterName},
+		{entry.Victim.CorporationID, &entry.Victim.CorporationName},
+		{entry.Victim.AllianceID, &entry.Victim.AllianceName},
+		{entry.Victim.FactionID, &entry.Victim.FactionName},
+	}
+
+	for i := range entry.Attackers {
+		attacker := &entry.Attackers[i]
+
+		entities = append(entities,
+			entityName{attacker.CharacterID, &attacker.CharacterName},
+			entityName{attacker.CorporationID, &attacker.CorporationName},
+			entityName{attacker.AllianceID, &attacker.AllianceName},
+			entityName{attacker.FactionID, &attacker.FactionName},
+		)
+	}
+
+	parser.fillEntityNames(entities)
+}
+
+// FillPilotNames resolves all character names missing from the given pilot statistics
+func (parser *Parser) FillPilotNames(pilots []reports.PilotStatistic) {
+	entities := make([]entityName, 0, len(pilots))
+
+	for i := range pilots {
+		entities = append(entities, entityName{pilots[i].CharacterID, &pilots[i].CharacterName})
+	}
+
+	parser.fillEntityNames(entities)
+}
+
+// fillEntityNames resolves the names of all given entities with an empty name, storing already known names in the resolver's cache
+func (parser *Parser) fillEntityNames(entities []entityName) {
+	ids := make([]int64, 0, len(entities))
+
+	for _, entity := range entities {
+		if entity.id <= 0 {
+			continue
+		}
+
+		if len(*entity.name) > 0 {
+			parser.names.Add(entity.id, *entity.name)
+		} else {
+			ids = append(ids, entity.id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+
+	names, err := parser.names.Resolve(ids)
+	if err != nil {
+		misc.Logger.Warnf("Failed to resolve all names: [%v]", err)
+	}
+
+	for _, entity := range entities {
+		if len(*entity.name) > 0 {
+			continue
+		}
+
+		name, ok := names[entity.id]
+		if ok {
+			*entity.name = name
+		}
+	}
+}
+
+// ResolveCharacterID resolves the ID of the character with the given name, returning an error if no character was found
+func (parser *Parser) ResolveCharacterID(name string) (int64, string, error) {
+	ids, err := parser.ids.FetchIDs([]string{name})
+	if err != nil {
+		return 0, "", err
+	}
+
+	if ids == nil || len(ids.Characters) == 0 {
+		return 0, "", fmt.Errorf("No character named %q found", name)
+	}
+
+	parser.names.Add(ids.Characters[0].ID, ids.Characters[0].Name)
+
+	return ids.Characters[0].ID, ids.Characters[0].Name, nil
+}
+
+// ResolveSolarSystemID resolves the ID of the solar system with the given name, returning an error if no solar system was found
+func (parser *Parser) ResolveSolarSystemID(name string) (int64, string, error) {
+	ids, err := parser.ids.FetchIDs([]string{name})
+	if err != nil {
+		return 0, "", err
+	}
+
+	if ids == nil || len(ids.Systems) == 0 {
+		return 0, "", fmt.Errorf("No solar system named %q found", name)
+	}
+
+	return ids.Systems[0].ID, ids.Systems[0].Name, nil
+}
@@ -1,43 +1,115 @@
 package parser
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"os"
+	"os/signal"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/dustin/go-humanize"
 
+	"github.com/morpheusxaut/eveslackkills/battle"
 	"github.com/morpheusxaut/eveslackkills/database"
+	"github.com/morpheusxaut/eveslackkills/lookup"
+	"github.com/morpheusxaut/eveslackkills/lookup/esi"
+	"github.com/morpheusxaut/eveslackkills/metrics"
 	"github.com/morpheusxaut/eveslackkills/misc"
 	"github.com/morpheusxaut/eveslackkills/models"
+	"github.com/morpheusxaut/eveslackkills/prices"
+	"github.com/morpheusxaut/eveslackkills/reports"
+	"github.com/morpheusxaut/eveslackkills/zkillboard"
+)
+
+const (
+	// fittingSummarySize represents the number of fitted modules listed in kill and loss messages
+	fittingSummarySize = 5
+	// killmailAttempts represents the number of attempts made to retrieve a single killmail if ESI responded with a temporary error
+	killmailAttempts = 3
 )
 
 // Parser represents the parser used for retrieving kills from zKillboard and posting them to Slack
 type Parser struct {
 	Corporations []*models.Corporation
 
-	crestClient *models.CRESTClient
-	scheduler   *time.Ticker
-	config      *misc.Configuration
-	database    database.Connection
+	lookup          lookup.Client
+	names           *lookup.NameResolver
+	killmails       lookup.KillmailLookup
+	ids             lookup.IDLookup
+	prices          prices.Provider
+	zkillboard      *zkillboard.Client
+	slackClient     *models.SlackClient
+	scheduler       *time.Ticker
+	interval        time.Duration
+	reportScheduler *time.Ticker
+	reportSchedules []*reports.Schedule
+	config          *misc.Configuration
+	database        database.Connection
+	battles         map[int64]*battle.Tracker
+	trigger         chan bool
+	stateMutex      sync.RWMutex
+	retryQueue      []*FailedPost
+	recentPosts     []RecentPost
+	status          Status
+	cycleID         int64
 }
 
 // SetupParser sets up a new parser with the given information
 func SetupParser(conf *misc.Configuration, db database.Connection, interval time.Duration) (*Parser, error) {
+	client, err := lookup.SetupLookup(conf, db)
+	if err != nil {
+		return nil, err
+	}
+
+	esiClient := esi.SetupClient(conf)
+
 	parser := &Parser{
-		Corporations: make([]*models.Corporation, 0),
-		crestClient:  models.NewCRESTClient("https://public-crest.eveonline.com/"),
-		scheduler:    time.NewTicker(interval),
-		config:       conf,
-		database:     db,
+		Corporations:    make([]*models.Corporation, 0),
+		lookup:          client,
+		names:           lookup.NewNameResolver(esiClient),
+		killmails:       esiClient,
+		ids:             esiClient,
+		zkillboard:      zkillboard.SetupClient(conf),
+		slackClient:     models.NewSlackClient(conf.SlackWebhookURL, conf.SlackAPIToken, conf.SlackChannel),
+		scheduler:       time.NewTicker(interval),
+		interval:        interval,
+		reportScheduler: time.NewTicker(time.Minute),
+		reportSchedules: make([]*reports.Schedule, 0),
+		config:          conf,
+		database:        db,
+		battles:         make(map[int64]*battle.Tracker),
+		trigger:         make(chan bool, 1),
+		retryQueue:      make([]*FailedPost, 0),
+		recentPosts:     make([]RecentPost, 0),
+		status:          Status{StartedAt: time.Now(), LastHeartbeat: time.Now(), CorporationUpdates: make(map[int64]time.Time)},
+	}
+
+	if conf.DailyDigest {
+		parser.reportSchedules = append(parser.reportSchedules, reports.NewSchedule(reports.KindDigest, reports.IntervalDaily, conf.DigestHour, time.Now()))
+	}
+	if conf.WeeklyDigest {
+		parser.reportSchedules = append(parser.reportSchedules, reports.NewSchedule(reports.KindDigest, reports.IntervalWeekly, conf.DigestHour, time.Now()))
+	}
+	if len(conf.LeaderboardInterval) > 0 {
+		interval, err := reports.ParseInterval(conf.LeaderboardInterval)
+		if err != nil {
+			return nil, err
+		}
+
+		parser.reportSchedules = append(parser.reportSchedules, reports.NewSchedule(reports.KindLeaderboard, interval, conf.DigestHour, time.Now()))
 	}
 
+	provider, err := prices.SetupProvider(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	parser.prices = provider
+
 	corporations, err := db.LoadAllCorporations()
 	if err != nil {
 		return nil, err
@@ -45,26 +117,57 @@ func SetupParser(conf *misc.Configuration, db database.Connection, interval time
 
 	parser.Corporations = corporations
 
+	for _, corporation := range corporations {
+		parser.status.CorporationUpdates[corporation.EVECorporationID] = time.Time{}
+	}
+
+	metrics.RetryQueueSize.Set(0)
+
 	return parser, nil
 }
 
 // Start starts the parsing operations and retrieves kills and losses for every tracked corporation regularly
 func (parser *Parser) Start() {
-	for _, corporation := range parser.Corporations {
-		err := parser.Update(corporation)
-		if err != nil {
-			misc.Logger.Errorf("Received error while updating corporation #%d: [%v]", corporation.EVECorporationID, err)
-		}
+	if parser.prices != nil {
+		go prices.RefreshPeriodically(parser.prices, time.Minute*time.Duration(parser.config.PriceRefreshInterval))
 	}
 
+	parser.UpdateAll()
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
 	for {
 		select {
 		case <-parser.scheduler.C:
-			for _, corporation := range parser.Corporations {
-				err := parser.Update(corporation)
-				if err != nil {
-					misc.Logger.Errorf("Received error while updating corporation #%d: [%v]", corporation.EVECorporationID, err)
-				}
+			err := parser.ReloadCorporations()
+			if err != nil {
+				misc.Logger.Errorf("Failed to reload corporations, continuing with previous configuration: [%v]", err)
+			}
+
+			parser.UpdateAll()
+		case now := <-parser.reportScheduler.C:
+			parser.RunScheduledReports(now)
+		case <-parser.trigger:
+			misc.Logger.Infof("Received update request, reloading corporations and running update")
+
+			err := parser.ReloadCorporations()
+			if err != nil {
+				misc.Logger.Errorf("Failed to reload corporations, continuing with previous configuration: [%v]", err)
+			}
+
+			parser.UpdateAll()
+		case <-hangup:
+			err := misc.ReopenLogFile()
+			if err != nil {
+				misc.Logger.Errorf("Failed to reopen log file, continuing with previous file: [%v]", err)
+			}
+
+			misc.Logger.Infof("Received SIGHUP, reopened log file and reloading corporations")
+
+			err = parser.ReloadCorporations()
+			if err != nil {
+				misc.Logger.Errorf("Failed to reload corporations, continuing with previous configuration: [%v]", err)
 			}
 		}
 	}
@@ -72,121 +175,304 @@ func (parser *Parser) Start() {
 
 // Update retrieves the latest kills and losses and posts them to Slack if required
 func (parser *Parser) Update(corporation *models.Corporation) error {
-	misc.Logger.Debugf("Running update for corporation #%d", corporation.EVECorporationID)
+	logger := parser.logger(corporation)
+
+	logger.Debugf("Running update for corporation #%d", corporation.EVECorporationID)
 
-	err := parser.FetchCRESTRoot()
+	defer metrics.UpdateDuration.ObserveSince(time.Now(), strconv.FormatInt(corporation.EVECorporationID, 10))
+
+	err := parser.lookup.RefreshServerVersion(logger)
+	parser.recordLookup(err)
 	if err != nil {
 		return err
 	}
 
-	misc.Logger.Tracef("Fetched CREST root before kill processing")
+	logger.Tracef("Refreshed lookup server version before kill processing")
 
 	kills, err := parser.FetchKills(corporation)
 	if err != nil {
 		return err
 	}
 
-	misc.Logger.Tracef("Fetched %d kills for corporation #%d", len(kills), corporation.EVECorporationID)
+	logger.Tracef("Fetched %d kills for corporation #%d", len(kills), corporation.EVECorporationID)
+	metrics.ZKillboardEntries.Add(float64(len(kills)), models.ZKillboardEntryTypeKill.String())
+
+	losses, err := parser.FetchLosses(corporation)
+	if err != nil {
+		return err
+	}
+
+	logger.Tracef("Fetched %d losses for corporation #%d", len(losses), corporation.EVECorporationID)
+	metrics.ZKillboardEntries.Add(float64(len(losses)), models.ZKillboardEntryTypeLoss.String())
+
+	parser.RetryFailedPosts(corporation)
+
+	timeline := models.MergeTimeline(kills, losses)
+
+	tracker := parser.BattleTracker(corporation)
+	tracker.Expire(time.Now())
+
+	entries := make([]models.ZKillboardTimelineEntry, 0, len(timeline))
+	clusters := make([]*battle.Battle, 0, len(timeline))
+	locations := make([]*models.CRESTLocationInfo, 0, len(timeline))
+
+	for _, item := range timeline {
+		parser.heartbeat()
 
-	for _, kill := range kills {
-		misc.Logger.Tracef("Processing kill #%d (victim %q)", kill.KillID, kill.Victim.CharacterName)
+		itemLogger := logger.WithField("killID", item.Entry.KillID)
 
-		info, err := parser.crestClient.FetchLocationInfo(kill.SolarSystemID)
+		itemLogger.Tracef("Processing %s #%d (victim %q)", item.Type, item.Entry.KillID, item.Entry.Victim.CharacterName)
+
+		err = parser.StoreKillmail(corporation, item)
+		if err != nil {
+			itemLogger.Warnf("Failed to store %s #%d in killmail history: [%v]", item.Type, item.Entry.KillID, err)
+		}
+
+		ignored, locationInfo, err := parser.IsIgnored(corporation, item)
 		if err != nil {
-			misc.Logger.Warnf("Failed to query region ID for solar system #%d", kill.SolarSystemID)
+			itemLogger.Warnf("Failed to query region ID for solar system #%d", item.Entry.SolarSystemID)
 			continue
+		} else if ignored {
+			continue
+		}
+
+		cluster, err := tracker.Add(item)
+		if err != nil {
+			itemLogger.Warnf("Failed to assign %s #%d to a battle: [%v]", item.Type, item.Entry.KillID, err)
+		}
+
+		entries = append(entries, item)
+		clusters = append(clusters, cluster)
+		locations = append(locations, locationInfo)
+	}
+
+	if parser.config.BacklogSummaryThreshold > 0 && len(entries) > parser.config.BacklogSummaryThreshold {
+		logger.Infof("Found backlog of %d entries for corporation #%d, sending summary instead of individual messages", len(entries), corporation.EVECorporationID)
+
+		err = parser.SendBacklogSummary(corporation, entries)
+		if err != nil {
+			return err
 		}
 
-		skip := false
-		for _, solarSystem := range corporation.IgnoredSolarSystems {
-			if strings.EqualFold(fmt.Sprintf("%d", solarSystem), info.RegionID) || solarSystem == kill.SolarSystemID {
-				skip = true
-				break
+		for _, item := range entries {
+			parser.heartbeat()
+
+			sent := parser.sendLossAlert(corporation, item)
+			parser.UpdateCheckpoint(corporation, item)
+
+			if sent {
+				parser.saveCheckpoints(logger, corporation)
 			}
 		}
 
-		if skip {
-			misc.Logger.Debugf("Found solar system ID %s for solar system #%d on ignore list, skipping kill", info.RegionID, kill.SolarSystemID)
+		return parser.database.SaveCorporationCheckpoints(logger, corporation)
+	}
+
+	reports := make([]*battle.Battle, 0)
+
+	for i, item := range entries {
+		parser.heartbeat()
+
+		itemLogger := logger.WithField("killID", item.Entry.KillID)
+
+		parser.sendLossAlert(corporation, item)
+
+		if clusters[i] != nil && tracker.IsBattle(clusters[i]) {
+			itemLogger.Debugf("Aggregating %s #%d into battle report for solar system #%d", item.Type, item.Entry.KillID, item.Entry.SolarSystemID)
+
+			found := false
+			for _, report := range reports {
+				if report == clusters[i] {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				reports = append(reports, clusters[i])
+			}
+
 			continue
 		}
 
-		misc.Logger.Tracef("Solar system ID %s for solar system #%d not found on ignore list (%v), posting kill", info.RegionID, kill.SolarSystemID, corporation.IgnoredSolarSystems)
+		err = parser.SendMessage(corporation, item.Entry, item.Type, locations[i])
+		if err != nil {
+			itemLogger.Warnf("Failed to send %s message, queueing it for retry: [%v]", item.Type, err)
+			parser.QueueRetry(corporation, item, locations[i], err)
+		}
+
+		parser.UpdateCheckpoint(corporation, item)
+		parser.saveCheckpoints(itemLogger, corporation)
+
+		itemLogger.Tracef("Finished processing %s #%d (victim %q)", item.Type, item.Entry.KillID, item.Entry.Victim.CharacterName)
+
+		// Wait in order to abide to Slack's message limit
+		time.Sleep(time.Second * 1)
+	}
+
+	for _, report := range reports {
+		parser.heartbeat()
 
-		err = parser.SendMessage(corporation, kill, true)
+		err = parser.SendBattleReport(corporation, report)
 		if err != nil {
-			misc.Logger.Warnf("Failed to send kill message: [%v]", err)
-			continue
+			logger.Warnf("Failed to send battle report for solar system #%d, queueing it for retry: [%v]", report.SolarSystemID, err)
+			parser.QueueBattleRetry(corporation, report, err)
 		}
 
-		if kill.KillID > corporation.LastKillID {
-			corporation.LastKillID = kill.KillID
+		for _, item := range report.Entries {
+			parser.UpdateCheckpoint(corporation, item)
 		}
 
-		misc.Logger.Tracef("Finished processing kill #%d (victim %q)", kill.KillID, kill.Victim.CharacterName)
+		parser.saveCheckpoints(logger, corporation)
+
+		logger.Tracef("Finished processing battle report for solar system #%d (%d entries)", report.SolarSystemID, len(report.Entries))
 
 		// Wait in order to abide to Slack's message limit
 		time.Sleep(time.Second * 1)
 	}
 
-	losses, err := parser.FetchLosses(corporation)
+	err = parser.database.SaveCorporationCheckpoints(logger, corporation)
 	if err != nil {
 		return err
 	}
 
-	misc.Logger.Tracef("Fetched %d losses for corporation #%d", len(losses), corporation.EVECorporationID)
+	logger.Debugf("Finished update for corporation #%d", corporation.EVECorporationID)
 
-	for _, loss := range losses {
-		misc.Logger.Tracef("Processing loss #%d (victim %q)", loss.KillID, loss.Victim.CharacterName)
+	return nil
+}
 
-		info, err := parser.crestClient.FetchLocationInfo(loss.SolarSystemID)
-		if err != nil {
-			misc.Logger.Warnf("Failed to query region ID for solar system #%d", loss.SolarSystemID)
-			continue
-		}
+// saveCheckpoints saves the current checkpoints of the corporation while an update is still posting messages, so already posted kills and losses are not posted again if the application is restarted before the update finished.
+// Errors are only logged since the checkpoints are saved again after the next message and at the end of the update
+func (parser *Parser) saveCheckpoints(logger *misc.Log, corporation *models.Corporation) {
+	err := parser.database.SaveCorporationCheckpoints(logger, corporation)
+	if err != nil {
+		logger.Warnf("Failed to save checkpoints of corporation #%d: [%v]", corporation.EVECorporationID, err)
+	}
+}
 
-		skip := false
-		for _, solarSystem := range corporation.IgnoredSolarSystems {
-			if strings.EqualFold(fmt.Sprintf("%d", solarSystem), info.RegionID) || solarSystem == loss.SolarSystemID {
-				skip = true
-				break
-			}
-		}
+// TriggerUpdate requests an immediate reload of the tracked corporations followed by an update, returning false if an update has already been requested
+func (parser *Parser) TriggerUpdate() bool {
+	select {
+	case parser.trigger <- true:
+		return true
+	default:
+		return false
+	}
+}
 
-		if skip {
-			misc.Logger.Debugf("Found solar system ID %s for solar system #%d on ignore list, skipping loss", info.RegionID, loss.SolarSystemID)
-			continue
-		}
+// ReloadCorporations reloads the tracked corporations from the database, applying changed settings and adding or removing corporations.
+// Checkpoints are only ever advanced, so kills and losses already processed by the running application are not posted again
+func (parser *Parser) ReloadCorporations() error {
+	corporations, err := parser.database.LoadAllCorporations()
+	if err != nil {
+		return err
+	}
 
-		misc.Logger.Tracef("Solar system ID %s for solar system #%d not found on ignore list (%v), posting loss", info.RegionID, loss.SolarSystemID, corporation.IgnoredSolarSystems)
+	running := make(map[int64]*models.Corporation, len(parser.Corporations))
+	for _, corporation := range parser.Corporations {
+		running[corporation.ID] = corporation
+	}
 
-		err = parser.SendMessage(corporation, loss, false)
-		if err != nil {
-			misc.Logger.Warnf("Failed to send loss message: [%v]", err)
+	reloaded := make([]*models.Corporation, 0, len(corporations))
+
+	for _, corporation := range corporations {
+		current, ok := running[corporation.ID]
+		if !ok {
+			parser.logger(corporation).Infof("Started tracking corporation #%d (%s)", corporation.EVECorporationID, corporation.Name)
+			reloaded = append(reloaded, corporation)
 			continue
 		}
 
-		if loss.KillID > corporation.LastLossID {
-			corporation.LastLossID = loss.KillID
+		delete(running, corporation.ID)
+
+		if current.LastKillID > corporation.LastKillID {
+			corporation.LastKillID = current.LastKillID
+		}
+		if current.LastLossID > corporation.LastLossID {
+			corporation.LastLossID = current.LastLossID
 		}
 
-		misc.Logger.Tracef("Finished processing loss #%d (victim %q)", loss.KillID, loss.Victim.CharacterName)
+		*current = *corporation
+		reloaded = append(reloaded, current)
+	}
 
-		// Wait in order to abide to Slack's message limit
-		time.Sleep(time.Second * 1)
+	for _, corporation := range running {
+		parser.logger(corporation).Infof("Stopped tracking corporation #%d (%s)", corporation.EVECorporationID, corporation.Name)
+		delete(parser.battles, corporation.ID)
+	}
+
+	parser.Corporations = reloaded
+
+	return nil
+}
+
+// IsIgnored checks whether the solar system or region of the given entry is on the ignore list of the corporation, returning the resolved location info for further use
+func (parser *Parser) IsIgnored(corporation *models.Corporation, item models.ZKillboardTimelineEntry) (bool, *models.CRESTLocationInfo, error) {
+	info, err := parser.lookup.FetchLocationInfo(parser.logger(corporation).WithField("killID", item.Entry.KillID), item.Entry.SolarSystemID)
+	if err != nil {
+		return false, nil, err
+	}
+
+	for _, solarSystem := range corporation.IgnoredSolarSystems {
+		if strings.EqualFold(fmt.Sprintf("%d", solarSystem), info.RegionID) || solarSystem == item.Entry.SolarSystemID {
+			parser.logger(corporation).Debugf("Found solar system ID %s for solar system #%d on ignore list, skipping %s", info.RegionID, item.Entry.SolarSystemID, item.Type)
+			return true, info, nil
+		}
 	}
 
-	_, err = parser.database.SaveCorporation(corporation)
+	parser.logger(corporation).Tracef("Solar system ID %s for solar system #%d not found on ignore list (%v), posting %s", info.RegionID, item.Entry.SolarSystemID, corporation.IgnoredSolarSystems, item.Type)
+
+	return false, info, nil
+}
+
+// StoreKillmail values the items of the given entry and saves it to the killmail history of the corporation
+func (parser *Parser) StoreKillmail(corporation *models.Corporation, item models.ZKillboardTimelineEntry) error {
+	item.Entry.ApplyItemValues(parser.prices)
+
+	killmail, err := models.NewKillmail(corporation, item)
 	if err != nil {
 		return err
 	}
 
-	misc.Logger.Debugf("Finished update for corporation #%d", corporation.EVECorporationID)
+	killmail.TotalValue = item.Entry.Value(parser.prices)
+
+	_, err = parser.database.SaveKillmail(parser.logger(corporation).WithField("killID", item.Entry.KillID), killmail)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-// SendMessage prepares a payload and sends a formatted kill/loss message to the Slack webhook
-func (parser *Parser) SendMessage(corporation *models.Corporation, entry models.ZKillboardEntry, killEntry bool) error {
+// UpdateCheckpoint advances the last kill or loss ID of the corporation to the given entry if it is newer
+func (parser *Parser) UpdateCheckpoint(corporation *models.Corporation, item models.ZKillboardTimelineEntry) {
+	switch item.Type {
+	case models.ZKillboardEntryTypeKill:
+		if item.Entry.KillID > corporation.LastKillID {
+			corporation.LastKillID = item.Entry.KillID
+		}
+	case models.ZKillboardEntryTypeLoss:
+		if item.Entry.KillID > corporation.LastLossID {
+			corporation.LastLossID = item.Entry.KillID
+		}
+	}
+}
+
+// BattleTracker returns the battle tracker used for the given corporation, creating a new one if required
+func (parser *Parser) BattleTracker(corporation *models.Corporation) *battle.Tracker {
+	tracker, ok := parser.battles[corporation.ID]
+	if !ok {
+		tracker = battle.NewTracker(parser.config.BattleMinimumKills, time.Minute*time.Duration(parser.config.BattleWindow))
+		parser.battles[corporation.ID] = tracker
+	}
+
+	return tracker
+}
+
+// SendMessage prepares a payload and sends a formatted kill/loss message to the Slack webhook, using the already resolved location info of the entry
+func (parser *Parser) SendMessage(corporation *models.Corporation, entry models.ZKillboardEntry, entryType models.ZKillboardEntryType, locationInfo *models.CRESTLocationInfo) error {
+	logger := parser.logger(corporation).WithField("killID", entry.KillID)
+
 	var payload models.SlackPayload
 	var kill models.SlackAttachment
 
@@ -202,13 +488,15 @@ func (parser *Parser) SendMessage(corporation *models.Corporation, entry models.
 
 	var damageTakenTitle string
 
+	parser.FillNames(&entry)
+
 	for _, attacker := range entry.Attackers {
 		if attacker.FinalBlow == 1 {
 			killer = attacker
 			killerCorpName = attacker.CorporationName
 		}
 		if attacker.CharacterID == 0 && attacker.FactionID != 0 {
-			misc.Logger.Debugf("Found attacker with character ID 0 and faction ID #%d for kill #%d", attacker.FactionID, entry.KillID)
+			logger.Debugf("Found attacker with character ID 0 and faction ID #%d for kill #%d", attacker.FactionID, entry.KillID)
 			continue
 		}
 		if attacker.CharacterID != 0 && attacker.DamageDone > highestDamageValue {
@@ -217,10 +505,15 @@ func (parser *Parser) SendMessage(corporation *models.Corporation, entry models.
 		}
 	}
 
-	shipName, err := parser.crestClient.FetchItemType(killer.ShipTypeID)
+	itemTypes, err := lookup.ResolveItemTypes(logger, parser.lookup, entry.TypeIDs(), parser.config.LookupConcurrency)
 	if err != nil {
-		misc.Logger.Warnf("Failed to query ship type ID #%d for killer of killboard entry #%d", killer.ShipTypeID, entry.KillID)
-		return err
+		logger.Warnf("Failed to resolve all item types of killboard entry #%d: [%v]", entry.KillID, err)
+	}
+
+	shipName, ok := itemTypes[killer.ShipTypeID]
+	if !ok {
+		logger.Warnf("Failed to query ship type ID #%d for killer of killboard entry #%d", killer.ShipTypeID, entry.KillID)
+		return fmt.Errorf("Failed to resolve ship type ID #%d", killer.ShipTypeID)
 	}
 
 	killerShipName = shipName.Name
@@ -231,10 +524,10 @@ func (parser *Parser) SendMessage(corporation *models.Corporation, entry models.
 		killerName = killer.CharacterName
 	}
 
-	shipName, err = parser.crestClient.FetchItemType(entry.Victim.ShipTypeID)
-	if err != nil {
-		misc.Logger.Warnf("Failed to query ship type ID #%d for victim of killboard entry #%d", entry.Victim.ShipTypeID, entry.KillID)
-		return err
+	shipName, ok = itemTypes[entry.Victim.ShipTypeID]
+	if !ok {
+		logger.Warnf("Failed to query ship type ID #%d for victim of killboard entry #%d", entry.Victim.ShipTypeID, entry.KillID)
+		return fmt.Errorf("Failed to resolve ship type ID #%d", entry.Victim.ShipTypeID)
 	}
 
 	victimShipName = shipName.Name
@@ -245,16 +538,10 @@ func (parser *Parser) SendMessage(corporation *models.Corporation, entry models.
 		victimName = entry.Victim.CharacterName
 	}
 
-	highestDamageShipName, err := parser.crestClient.FetchItemType(highestDamageDealer.ShipTypeID)
-	if err != nil {
-		misc.Logger.Warnf("Failed to query ship type ID #%d for highest damage dealer of killboard entry #%d", highestDamageDealer.ShipTypeID, entry.KillID)
-		return err
-	}
-
-	locationInfo, err := parser.crestClient.FetchLocationInfo(entry.SolarSystemID)
-	if err != nil {
-		misc.Logger.Warnf("Failed to query location info for solar system ID #%d of killboard entry #%d", entry.SolarSystemID, entry.KillID)
-		return err
+	highestDamageShipName, ok := itemTypes[highestDamageDealer.ShipTypeID]
+	if !ok {
+		logger.Warnf("Failed to query ship type ID #%d for highest damage dealer of killboard entry #%d", highestDamageDealer.ShipTypeID, entry.KillID)
+		return fmt.Errorf("Failed to resolve ship type ID #%d", highestDamageDealer.ShipTypeID)
 	}
 
 	var victimCorporation string
@@ -267,7 +554,7 @@ func (parser *Parser) SendMessage(corporation *models.Corporation, entry models.
 
 	var comment string
 
-	if killEntry {
+	if entryType == models.ZKillboardEntryTypeKill {
 		comment = corporation.KillComment
 		kill.Color = "good"
 		damageTakenTitle = "Damage dealt"
@@ -305,9 +592,11 @@ func (parser *Parser) SendMessage(corporation *models.Corporation, entry models.
 		Short: true,
 	})
 
+	breakdown := entry.ValueBreakdown(parser.prices)
+
 	kill.Fields = append(kill.Fields, models.SlackField{
 		Title: "ISK value",
-		Value: fmt.Sprintf("%s ISK", humanize.Commaf(entry.Misc.TotalValue)),
+		Value: fmt.Sprintf("%s ISK", humanize.Commaf(breakdown.Total)),
 		Short: true,
 	})
 
@@ -353,97 +642,209 @@ func (parser *Parser) SendMessage(corporation *models.Corporation, entry models.
 		Short: true,
 	})
 
-	payload.Attachments = append(payload.Attachments, kill)
+	if breakdown.Dropped > 0 || breakdown.Destroyed > 0 {
+		kill.Fields = append(kill.Fields, models.SlackField{
+			Title: "Dropped value",
+			Value: fmt.Sprintf("%s ISK", humanize.Commaf(breakdown.Dropped)),
+			Short: true,
+		})
+
+		kill.Fields = append(kill.Fields, models.SlackField{
+			Title: "Destroyed value",
+			Value: fmt.Sprintf("%s ISK", humanize.Commaf(breakdown.Destroyed)),
+			Short: true,
+		})
+	}
 
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return err
+	if breakdown.Fitted > 0 {
+		kill.Fields = append(kill.Fields, models.SlackField{
+			Title: "Fitted value",
+			Value: fmt.Sprintf("%s ISK", humanize.Commaf(breakdown.Fitted)),
+			Short: true,
+		})
 	}
 
-	req, err := http.NewRequest("POST", parser.config.SlackWebhookURL, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return err
+	if parser.config.ShowFitting {
+		fitting := parser.FittingSummary(entry, itemTypes, fittingSummarySize)
+		if len(fitting) > 0 {
+			kill.Fields = append(kill.Fields, models.SlackField{
+				Title: "Fitting",
+				Value: fitting,
+				Short: false,
+			})
+		}
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	if len(parser.config.SlackSigningSecret) > 0 {
+		kill.CallbackID = KillmailCallbackID(corporation.EVECorporationID, entry.KillID, entryType)
+		kill.Actions = killmailActions
+	}
+
+	payload.Attachments = append(payload.Attachments, kill)
+
+	_, err = parser.slackClient.PostPayload(&payload)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		respBody, err := ioutil.ReadAll(resp.Body)
+	parser.recordPost(corporation, entry.KillID, entryType.String(), comment)
+
+	return nil
+}
+
+// CompleteEntries retrieves the full killmails for all given entries of the corporation only providing zKillboard information.
+// Entries are completed in order of their kill IDs, stopping at the first killmail which could not be retrieved but may succeed later, so checkpoints are never advanced past it.
+// Killmails rejected by ESI or not matching their entry can never be completed and are logged and skipped. Returns the completed entries along with the ID of the entry the batch stopped at, 0 if all entries were processed
+func (parser *Parser) CompleteEntries(corporation *models.Corporation, entries []models.ZKillboardEntry) ([]models.ZKillboardEntry, int64) {
+	sort.Sort(models.ByKillID(entries))
+
+	completed := make([]models.ZKillboardEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.HasKillmail() {
+			completed = append(completed, entry)
+			continue
+		}
+
+		parser.heartbeat()
+
+		logger := parser.logger(corporation).WithField("killID", entry.KillID)
+
+		killmail, err := parser.fetchKillmail(logger, entry.KillID, entry.Misc.Hash)
+		if err != nil && !isRejected(err) {
+			logger.Warnf("Failed to fetch killmail #%d, postponing it and all newer entries until the next update: [%v]", entry.KillID, err)
+			return completed, entry.KillID
+		} else if err == nil {
+			err = entry.ApplyESIKillmail(killmail)
+		}
+
 		if err != nil {
-			return err
+			logger.Errorf("Failed to complete killmail #%d, skipping it: [%v]", entry.KillID, err)
+			continue
 		}
 
-		return fmt.Errorf("Failed to send message: %s (status code: %d)", string(respBody), resp.StatusCode)
+		completed = append(completed, entry)
 	}
 
-	return nil
+	return completed, 0
 }
 
-// FetchCRESTRoot fetches the CREST root endpoint and checks the server version before performing other lookup requests
-func (parser *Parser) FetchCRESTRoot() error {
-	root, err := parser.crestClient.FetchRoot()
-	if err != nil {
-		return err
-	}
+// fetchKillmail retrieves the full killmail with the given ID and hash, retrying a limited number of times if ESI responded with a temporary error or timed out
+func (parser *Parser) fetchKillmail(logger *misc.Log, killID int64, hash string) (*models.ESIKillmail, error) {
+	var err error
 
-	parser.crestClient.CheckServerVersion(root.ServerVersion)
+	for attempt := 1; attempt <= killmailAttempts; attempt++ {
+		logger.Tracef("Fetching killmail #%d with hash %q (attempt %d)", killID, hash, attempt)
 
-	return nil
+		var killmail *models.ESIKillmail
+
+		killmail, err = parser.killmails.FetchKillmail(logger, killID, hash)
+		if err == nil {
+			return killmail, nil
+		}
+
+		if !isTemporary(err) {
+			return nil, err
+		}
+
+		time.Sleep(time.Second * time.Duration(attempt))
+	}
+
+	return nil, err
 }
 
-// FetchKills retrieves and parses the latest kills from the zKillboard API
-func (parser *Parser) FetchKills(corporation *models.Corporation) ([]models.ZKillboardEntry, error) {
-	resp, err := http.Get(fmt.Sprintf("https://zkillboard.com/api/kills/corporationID/%d/afterKillID/%d", corporation.EVECorporationID, corporation.LastKillID))
-	if err != nil {
-		return nil, err
+// isTemporary returns whether the given error is a timeout or a temporary error, in which case the failed request may succeed if retried
+func isTemporary(err error) bool {
+	timeout, ok := err.(interface {
+		Timeout() bool
+	})
+	if ok && timeout.Timeout() {
+		return true
 	}
 
-	defer resp.Body.Close()
+	temporary, ok := err.(interface {
+		Temporary() bool
+	})
 
-	response, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	return ok && temporary.Temporary()
+}
+
+// isRejected returns whether ESI rejected the request for the given error with a client error, in which case retrying the request will not succeed
+func isRejected(err error) bool {
+	statusErr, ok := err.(*esi.StatusError)
+	if !ok {
+		return false
 	}
 
-	var kills []models.ZKillboardEntry
+	return statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 && !statusErr.Temporary()
+}
 
-	err = json.Unmarshal(response, &kills)
-	if err != nil {
-		return nil, err
+// FittingSummary returns a compact list of up to limit of the most valuable modules fitted to the ship of the given entry, using the already resolved item types. A limit of 0 lists all fitted modules, otherwise nothing is listed if no module values are known
+func (parser *Parser) FittingSummary(entry models.ZKillboardEntry, itemTypes map[int64]*models.CRESTItemType, limit int) string {
+	modules, ranked := entry.FittedModules(parser.prices, limit)
+
+	// Without any module values, a limited selection would merely list the first slots instead of the most valuable modules
+	if !ranked && limit > 0 {
+		return ""
 	}
 
-	sort.Sort(models.ByKillID(kills))
+	lines := make([]string, 0, len(modules))
+	for _, module := range modules {
+		name := fmt.Sprintf("#%d", module.TypeID)
+		itemType, ok := itemTypes[module.TypeID]
+		if ok {
+			name = itemType.Name
+		}
 
-	return kills, nil
+		if module.Value > 0 {
+			lines = append(lines, fmt.Sprintf("%dx %s (%s ISK)", module.Quantity, name, humanize.Commaf(module.Value)))
+		} else {
+			lines = append(lines, fmt.Sprintf("%dx %s", module.Quantity, name))
+		}
+	}
+
+	return strings.Join(lines, "\n")
 }
 
-// FetchLosses retrieves and parses the latest losses from the zKillboard API
-func (parser *Parser) FetchLosses(corporation *models.Corporation) ([]models.ZKillboardEntry, error) {
-	resp, err := http.Get(fmt.Sprintf("https://zkillboard.com/api/losses/corporationID/%d/afterKillID/%d", corporation.EVECorporationID, corporation.LastLossID))
-	if err != nil {
-		return nil, err
+// pageLimit returns the maximum number of zKillboard pages retrieved during an update. Backlogs are fetched completely if they are summarised, otherwise the limit prevents flooding Slack by posting large backlogs over multiple updates
+func (parser *Parser) pageLimit() int {
+	if parser.config.BacklogSummaryThreshold > 0 {
+		return 0
 	}
 
-	defer resp.Body.Close()
+	return zkillboard.MaxPages
+}
 
-	response, err := ioutil.ReadAll(resp.Body)
+// FetchKills retrieves and parses the latest kills from the zKillboard API. If the page limit was reached, only the oldest kills are returned and the remaining ones are retrieved during the next update
+func (parser *Parser) FetchKills(corporation *models.Corporation) ([]models.ZKillboardEntry, error) {
+	kills, truncated, err := parser.zkillboard.FetchKills(corporation.EVECorporationID, corporation.LastKillID, parser.pageLimit())
 	if err != nil {
 		return nil, err
+	} else if truncated {
+		parser.logger(corporation).Infof("Found more than %d pages of new kills for corporation #%d, processing the oldest %d kills and retrieving the remaining ones during the next update", zkillboard.MaxPages, corporation.EVECorporationID, len(kills))
 	}
 
-	var losses []models.ZKillboardEntry
+	kills, stoppedAt := parser.CompleteEntries(corporation, kills)
+	if stoppedAt > 0 {
+		parser.logger(corporation).Warnf("Processing %d kills of corporation #%d older than #%d, retrieving the remaining kills during the next update", len(kills), corporation.EVECorporationID, stoppedAt)
+	}
 
-	err = json.Unmarshal(response, &losses)
+	return kills, nil
+}
+
+// FetchLosses retrieves and parses the latest losses from the zKillboard API. If the page limit was reached, only the oldest losses are returned and the remaining ones are retrieved during the next update
+func (parser *Parser) FetchLosses(corporation *models.Corporation) ([]models.ZKillboardEntry, error) {
+	losses, truncated, err := parser.zkillboard.FetchLosses(corporation.EVECorporationID, corporation.LastLossID, parser.pageLimit())
 	if err != nil {
 		return nil, err
+	} else if truncated {
+		parser.logger(corporation).Infof("Found more than %d pages of new losses for corporation #%d, processing the oldest %d losses and retrieving the remaining ones during the next update", zkillboard.MaxPages, corporation.EVECorporationID, len(losses))
 	}
 
-	sort.Sort(models.ByKillID(losses))
+	losses, stoppedAt := parser.CompleteEntries(corporation, losses)
+	if stoppedAt > 0 {
+		parser.logger(corporation).Warnf("Processing %d losses of corporation #%d older than #%d, retrieving the remaining losses during the next update", len(losses), corporation.EVECorporationID, stoppedAt)
+	}
 
 	return losses, nil
 }
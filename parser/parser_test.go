@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/kdar/factorlog"
+
+	"github.com/morpheusxaut/eveslackkills/battle"
+	"github.com/morpheusxaut/eveslackkills/database"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+func init() {
+	misc.Logger = misc.NewLog(ioutil.Discard, misc.LogFormatText, factorlog.CRITICAL)
+}
+
+// fakeDatabase returns a fixed list of corporations, all other methods of the connection are left unimplemented
+type fakeDatabase struct {
+	database.Connection
+
+	corporations []*models.Corporation
+	err          error
+}
+
+// LoadAllCorporations returns copies of the configured corporations, simulating freshly loaded rows
+func (db *fakeDatabase) LoadAllCorporations() ([]*models.Corporation, error) {
+	if db.err != nil {
+		return nil, db.err
+	}
+
+	corporations := make([]*models.Corporation, 0, len(db.corporations))
+	for _, corporation := range db.corporations {
+		loaded := *corporation
+		corporations = append(corporations, &loaded)
+	}
+
+	return corporations, nil
+}
+
+func TestReloadCorporations(t *testing.T) {
+	tracked := &models.Corporation{ID: 1, EVECorporationID: 98388312, LastKillID: 100, LastLossID: 50, Name: "Old name"}
+	removed := &models.Corporation{ID: 3, EVECorporationID: 98000003, LastKillID: 10, LastLossID: 10}
+
+	db := &fakeDatabase{
+		corporations: []*models.Corporation{
+			{ID: 1, EVECorporationID: 98388312, LastKillID: 90, LastLossID: 60, Name: "New name", AlertValue: 1000},
+			{ID: 2, EVECorporationID: 98000002, LastKillID: 20, LastLossID: 30},
+		},
+	}
+
+	parser := &Parser{
+		Corporations: []*models.Corporation{tracked, removed},
+		database:     db,
+		battles: map[int64]*battle.Tracker{
+			1: battle.NewTracker(0, 0),
+			3: battle.NewTracker(0, 0),
+		},
+	}
+
+	err := parser.ReloadCorporations()
+	if err != nil {
+		t.Fatalf("Failed to reload corporations: %v", err)
+	}
+
+	if len(parser.Corporations) != 2 {
+		t.Fatalf("Expected 2 corporations, got %d", len(parser.Corporations))
+	}
+
+	if parser.Corporations[0] != tracked {
+		t.Errorf("Expected tracked corporation to be updated in place")
+	}
+
+	// The running checkpoint is ahead for kills, while the stored one is ahead for losses (e.g. after a backfill)
+	if tracked.LastKillID != 100 || tracked.LastLossID != 60 {
+		t.Errorf("Expected checkpoints 100 and 60, got %d and %d", tracked.LastKillID, tracked.LastLossID)
+	}
+
+	if tracked.Name != "New name" || tracked.AlertValue != 1000 {
+		t.Errorf("Expected settings to be reloaded, got name %q and alert value %.0f", tracked.Name, tracked.AlertValue)
+	}
+
+	added := parser.Corporations[1]
+	if added.ID != 2 || added.LastKillID != 20 || added.LastLossID != 30 {
+		t.Errorf("Expected new corporation #2 with stored checkpoints, got %+v", added)
+	}
+
+	if _, ok := parser.battles[3]; ok {
+		t.Errorf("Expected battles of removed corporation to be dropped")
+	}
+	if _, ok := parser.battles[1]; !ok {
+		t.Errorf("Expected battles of tracked corporation to be kept")
+	}
+}
+
+func TestReloadCorporationsError(t *testing.T) {
+	tracked := &models.Corporation{ID: 1, EVECorporationID: 98388312, LastKillID: 100, LastLossID: 50}
+
+	parser := &Parser{
+		Corporations: []*models.Corporation{tracked},
+		database:     &fakeDatabase{err: fmt.Errorf("Connection lost")},
+		battles:      make(map[int64]*battle.Tracker),
+	}
+
+	err := parser.ReloadCorporations()
+	if err == nil {
+		t.Errorf("Expected error to be returned")
+	}
+
+	if len(parser.Corporations) != 1 || parser.Corporations[0] != tracked {
+		t.Errorf("Expected previous corporations to be kept")
+	}
+}
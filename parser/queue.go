@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/battle"
+	"github.com/morpheusxaut/eveslackkills/metrics"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	// maxRetryAttempts represents the number of attempts made to post a kill or loss message before it is dropped from the retry queue
+	maxRetryAttempts = 5
+	// recentPostsSize represents the number of recently posted messages kept for display
+	recentPostsSize = 50
+)
+
+// FailedPost represents a kill or loss message or a battle report which could not be posted to Slack and will be retried during the next update
+type FailedPost struct {
+	CorporationID int64                          `json:"corporationID"`
+	KillID        int64                          `json:"killID"`
+	Type          string                         `json:"type"`
+	Attempts      int                            `json:"attempts"`
+	LastError     string                         `json:"lastError"`
+	LastAttempt   time.Time                      `json:"lastAttempt"`
+	Item          models.ZKillboardTimelineEntry `json:"-"`
+	LocationInfo  *models.CRESTLocationInfo      `json:"-"`
+	Battle        *battle.Battle                 `json:"-"`
+
+	reportCount int
+}
+
+// RecentPost represents a message recently posted to Slack
+type RecentPost struct {
+	CorporationID int64     `json:"corporationID"`
+	KillID        int64     `json:"killID"`
+	Type          string    `json:"type"`
+	Title         string    `json:"title"`
+	PostedAt      time.Time `json:"postedAt"`
+}
+
+// RetryQueue returns a copy of all messages currently waiting to be retried
+func (parser *Parser) RetryQueue() []FailedPost {
+	parser.stateMutex.RLock()
+	defer parser.stateMutex.RUnlock()
+
+	queue := make([]FailedPost, 0, len(parser.retryQueue))
+	for _, failed := range parser.retryQueue {
+		queue = append(queue, *failed)
+	}
+
+	return queue
+}
+
+// RecentPosts returns a copy of the most recently posted messages, newest first
+func (parser *Parser) RecentPosts() []RecentPost {
+	parser.stateMutex.RLock()
+	defer parser.stateMutex.RUnlock()
+
+	posts := make([]RecentPost, 0, len(parser.recentPosts))
+	for i := len(parser.recentPosts) - 1; i >= 0; i-- {
+		posts = append(posts, parser.recentPosts[i])
+	}
+
+	return posts
+}
+
+// QueueRetry adds the given kill or loss to the retry queue after posting it failed
+func (parser *Parser) QueueRetry(corporation *models.Corporation, item models.ZKillboardTimelineEntry, locationInfo *models.CRESTLocationInfo, err error) {
+	parser.stateMutex.Lock()
+	defer parser.stateMutex.Unlock()
+
+	parser.retryQueue = append(parser.retryQueue, &FailedPost{
+		CorporationID: corporation.EVECorporationID,
+		KillID:        item.Entry.KillID,
+		Type:          item.Type.String(),
+		Attempts:      1,
+		LastError:     err.Error(),
+		LastAttempt:   time.Now(),
+		Item:          item,
+		LocationInfo:  locationInfo,
+	})
+
+	metrics.RetryQueueSize.Set(float64(len(parser.retryQueue)))
+}
+
+// QueueBattleRetry adds the given battle report to the retry queue after posting it failed. Reports already waiting to be retried are only updated
+func (parser *Parser) QueueBattleRetry(corporation *models.Corporation, report *battle.Battle, err error) {
+	parser.stateMutex.Lock()
+	defer parser.stateMutex.Unlock()
+
+	for _, failed := range parser.retryQueue {
+		if failed.Battle == report {
+			failed.LastError = err.Error()
+			failed.LastAttempt = time.Now()
+			return
+		}
+	}
+
+	parser.retryQueue = append(parser.retryQueue, &FailedPost{
+		CorporationID: corporation.EVECorporationID,
+		Type:          "battle",
+		Attempts:      1,
+		LastError:     err.Error(),
+		LastAttempt:   time.Now(),
+		Battle:        report,
+		reportCount:   report.ReportCount,
+	})
+
+	metrics.RetryQueueSize.Set(float64(len(parser.retryQueue)))
+}
+
+// RetryFailedPosts tries to post all queued messages of the given corporation again, dropping messages which failed too often
+func (parser *Parser) RetryFailedPosts(corporation *models.Corporation) {
+	parser.stateMutex.RLock()
+	pending := make([]*FailedPost, 0)
+	for _, failed := range parser.retryQueue {
+		if failed.CorporationID == corporation.EVECorporationID {
+			pending = append(pending, failed)
+		}
+	}
+	parser.stateMutex.RUnlock()
+
+	for _, failed := range pending {
+		parser.heartbeat()
+
+		var err error
+
+		if failed.Battle != nil {
+			// The report has been sent by a later update of the battle in the meantime, so retrying would post it twice
+			if failed.Battle.ReportCount > failed.reportCount {
+				parser.stateMutex.Lock()
+				parser.removeRetry(failed)
+				parser.stateMutex.Unlock()
+				continue
+			}
+
+			parser.logger(corporation).Debugf("Retrying battle report for solar system #%d (attempt %d)", failed.Battle.SolarSystemID, failed.Attempts+1)
+
+			err = parser.SendBattleReport(corporation, failed.Battle)
+		} else {
+			parser.logger(corporation).WithField("killID", failed.KillID).Debugf("Retrying %s message #%d (attempt %d)", failed.Type, failed.KillID, failed.Attempts+1)
+
+			err = parser.SendMessage(corporation, failed.Item.Entry, failed.Item.Type, failed.LocationInfo)
+		}
+
+		parser.stateMutex.Lock()
+		if err == nil {
+			parser.removeRetry(failed)
+		} else {
+			failed.Attempts++
+			failed.LastError = err.Error()
+			failed.LastAttempt = time.Now()
+
+			if failed.Attempts >= maxRetryAttempts {
+				parser.logger(corporation).WithField("killID", failed.KillID).Errorf("Dropping %s message #%d after %d failed attempts: [%v]", failed.Type, failed.KillID, failed.Attempts, err)
+				parser.removeRetry(failed)
+			}
+		}
+		parser.stateMutex.Unlock()
+
+		// Wait in order to abide to Slack's message limit
+		time.Sleep(time.Second * 1)
+	}
+}
+
+// removeRetry removes the given message from the retry queue, requiring the state mutex to be held
+func (parser *Parser) removeRetry(failed *FailedPost) {
+	for i, queued := range parser.retryQueue {
+		if queued == failed {
+			parser.retryQueue = append(parser.retryQueue[:i], parser.retryQueue[i+1:]...)
+			break
+		}
+	}
+
+	metrics.RetryQueueSize.Set(float64(len(parser.retryQueue)))
+}
+
+// recordPost adds a successfully posted message to the list of recent posts
+func (parser *Parser) recordPost(corporation *models.Corporation, killID int64, postType string, title string) {
+	parser.stateMutex.Lock()
+	defer parser.stateMutex.Unlock()
+
+	parser.recentPosts = append(parser.recentPosts, RecentPost{
+		CorporationID: corporation.EVECorporationID,
+		KillID:        killID,
+		Type:          postType,
+		Title:         title,
+		PostedAt:      time.Now(),
+	})
+
+	if len(parser.recentPosts) > recentPostsSize {
+		parser.recentPosts = parser.recentPosts[len(parser.recentPosts)-recentPostsSize:]
+	}
+
+	metrics.Posts.Inc(strconv.FormatInt(corporation.EVECorporationID, 10), postType)
+}
+
+// SendResponse sends the given payload as a delayed response to a Slack slash command or interaction using the provided response URL
+func (parser *Parser) SendResponse(responseURL string, payload *models.SlackPayload) error {
+	return parser.slackClient.PostResponse(responseURL, payload)
+}
@@ -0,0 +1,252 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+	"github.com/morpheusxaut/eveslackkills/reports"
+)
+
+const (
+	// digestRankingSize represents the number of pilots and solar systems listed in digests
+	digestRankingSize = 5
+)
+
+// RunScheduledReports posts all scheduled reports due at the given time for every tracked corporation
+func (parser *Parser) RunScheduledReports(now time.Time) {
+	for _, schedule := range parser.reportSchedules {
+		if !schedule.Due(now) {
+			continue
+		}
+
+		from, to := schedule.Period()
+		name := fmt.Sprintf("%s %s", schedule.Interval, schedule.Kind)
+
+		parser.logger(nil).Debugf("Running %s for period %v - %v", strings.ToLower(name), from, to)
+
+		for _, corporation := range parser.Corporations {
+			var err error
+
+			switch schedule.Kind {
+			case reports.KindDigest:
+				err = parser.SendDigest(corporation, name, from, to)
+			case reports.KindLeaderboard:
+				err = parser.SendLeaderboard(corporation, name, from, to)
+			}
+
+			if err != nil {
+				parser.logger(corporation).Errorf("Failed to send %s for corporation #%d: [%v]", strings.ToLower(name), corporation.EVECorporationID, err)
+			}
+
+			// Wait in order to abide to Slack's message limit
+			time.Sleep(time.Second * 1)
+		}
+
+		schedule.Advance(now)
+	}
+}
+
+// SendDigest prepares a payload summarising the stored kills and losses of the corporation within the given period and sends it to Slack
+func (parser *Parser) SendDigest(corporation *models.Corporation, name string, from time.Time, to time.Time) error {
+	var payload models.SlackPayload
+	var summary models.SlackAttachment
+
+	killmails, err := parser.database.LoadKillmails(corporation.ID, from, to)
+	if err != nil {
+		return err
+	}
+
+	digest := reports.GenerateDigest(corporation, killmails, from, to, digestRankingSize, parser.prices)
+	parser.FillPilotNames(digest.TopKillers)
+
+	title := fmt.Sprintf("%s for %s: %s - %s", name, CorporationName(corporation), from.Format("2006-01-02"), to.Add(-time.Second).Format("2006-01-02"))
+
+	if digest.ISKDestroyed >= digest.ISKLost {
+		summary.Color = "good"
+	} else {
+		summary.Color = "danger"
+	}
+
+	summary.Fallback = title
+	summary.Title = title
+	summary.TitleLink = fmt.Sprintf("https://zkillboard.com/corporation/%d/", corporation.EVECorporationID)
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "Kills",
+		Value: humanize.Comma(int64(digest.Kills)),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "Losses",
+		Value: humanize.Comma(int64(digest.Losses)),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "ISK destroyed",
+		Value: fmt.Sprintf("%s ISK", humanize.Commaf(digest.ISKDestroyed)),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "ISK lost",
+		Value: fmt.Sprintf("%s ISK", humanize.Commaf(digest.ISKLost)),
+		Short: true,
+	})
+
+	summary.Fields = append(summary.Fields, models.SlackField{
+		Title: "Efficiency",
+		Value: fmt.Sprintf("%.1f%%", digest.Efficiency()),
+		Short: true,
+	})
+
+	if digest.MostExpensiveLoss != nil {
+		loss := digest.MostExpensiveLoss
+		parser.FillNames(&loss.Entry)
+
+		shipName := fmt.Sprintf("#%d", loss.Entry.Victim.ShipTypeID)
+		logger := parser.logger(corporation).WithField("killID", loss.KillID)
+
+		itemType, err := parser.lookup.FetchItemType(logger, loss.Entry.Victim.ShipTypeID)
+		if err != nil {
+			logger.Warnf("Failed to query ship type ID #%d for most expensive loss #%d", loss.Entry.Victim.ShipTypeID, loss.KillID)
+		} else {
+			shipName = itemType.Name
+		}
+
+		summary.Fields = append(summary.Fields, models.SlackField{
+			Title: "Most expensive loss",
+			Value: fmt.Sprintf("<https://zkillboard.com/kill/%d/|%s> (%s, %s ISK)", loss.KillID, shipName, loss.Entry.Victim.CharacterName, humanize.Commaf(digest.MostExpensiveLossValue)),
+			Short: true,
+		})
+	}
+
+	if len(digest.TopKillers) > 0 {
+		lines := make([]string, 0, len(digest.TopKillers))
+		for i, killer := range digest.TopKillers {
+			lines = append(lines, fmt.Sprintf("%d. <https://zkillboard.com/character/%d/|%s> (%s kills)", i+1, killer.CharacterID, killer.CharacterName, humanize.Comma(int64(killer.Value))))
+		}
+
+		summary.Fields = append(summary.Fields, models.SlackField{
+			Title: "Top killers",
+			Value: strings.Join(lines, "\n"),
+			Short: true,
+		})
+	}
+
+	if len(digest.BusiestSystems) > 0 {
+		lines := make([]string, 0, len(digest.BusiestSystems))
+		for i, system := range digest.BusiestSystems {
+			systemName := fmt.Sprintf("#%d", system.SolarSystemID)
+			locationInfo, err := parser.lookup.FetchLocationInfo(parser.logger(corporation), system.SolarSystemID)
+			if err != nil {
+				parser.logger(corporation).Warnf("Failed to query location info for solar system ID #%d of digest", system.SolarSystemID)
+			} else {
+				systemName = locationInfo.SolarSystemName
+			}
+
+			lines = append(lines, fmt.Sprintf("%d. <https://zkillboard.com/system/%d/|%s> (%s kills/losses)", i+1, system.SolarSystemID, systemName, humanize.Comma(int64(system.Count))))
+		}
+
+		summary.Fields = append(summary.Fields, models.SlackField{
+			Title: "Busiest systems",
+			Value: strings.Join(lines, "\n"),
+			Short: true,
+		})
+	}
+
+	payload.Attachments = append(payload.Attachments, summary)
+
+	_, err = parser.slackClient.PostPayload(&payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendLeaderboard prepares a payload ranking the pilots of the corporation by their kills within the given period and sends it to Slack
+func (parser *Parser) SendLeaderboard(corporation *models.Corporation, name string, from time.Time, to time.Time) error {
+	var payload models.SlackPayload
+	var summary models.SlackAttachment
+
+	killmails, err := parser.database.LoadKillmails(corporation.ID, from, to)
+	if err != nil {
+		return err
+	}
+
+	leaderboard := reports.GenerateLeaderboard(corporation, killmails, from, to, digestRankingSize, parser.prices)
+	parser.FillPilotNames(leaderboard.FinalBlows)
+	parser.FillPilotNames(leaderboard.DamageDone)
+	parser.FillPilotNames(leaderboard.KillsParticipated)
+	parser.FillPilotNames(leaderboard.ISKDestroyed)
+	parser.FillPilotNames(leaderboard.SoloKills)
+
+	title := fmt.Sprintf("%s for %s: %s - %s", name, CorporationName(corporation), from.Format("2006-01-02"), to.Add(-time.Second).Format("2006-01-02"))
+
+	summary.Color = "#439FE0"
+	summary.Fallback = title
+	summary.Title = title
+	summary.TitleLink = fmt.Sprintf("https://zkillboard.com/corporation/%d/", corporation.EVECorporationID)
+
+	summary.Fields = append(summary.Fields, leaderboardField("Final blows", leaderboard.FinalBlows, func(value float64) string {
+		return fmt.Sprintf("%s final blows", humanize.Comma(int64(value)))
+	}))
+
+	summary.Fields = append(summary.Fields, leaderboardField("Damage done", leaderboard.DamageDone, func(value float64) string {
+		return fmt.Sprintf("%s damage", humanize.Comma(int64(value)))
+	}))
+
+	summary.Fields = append(summary.Fields, leaderboardField("Kills participated", leaderboard.KillsParticipated, func(value float64) string {
+		return fmt.Sprintf("%s kills", humanize.Comma(int64(value)))
+	}))
+
+	summary.Fields = append(summary.Fields, leaderboardField("ISK destroyed", leaderboard.ISKDestroyed, func(value float64) string {
+		return fmt.Sprintf("%s ISK", humanize.Commaf(value))
+	}))
+
+	summary.Fields = append(summary.Fields, leaderboardField("Solo kills", leaderboard.SoloKills, func(value float64) string {
+		return fmt.Sprintf("%s solo kills", humanize.Comma(int64(value)))
+	}))
+
+	payload.Attachments = append(payload.Attachments, summary)
+
+	_, err = parser.slackClient.PostPayload(&payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// leaderboardField creates a Slack field listing the given pilot ranking, formatting values using the provided function
+func leaderboardField(title string, ranking []reports.PilotStatistic, format func(value float64) string) models.SlackField {
+	lines := make([]string, 0, len(ranking))
+	for i, pilot := range ranking {
+		lines = append(lines, fmt.Sprintf("%d. <https://zkillboard.com/character/%d/|%s> (%s)", i+1, pilot.CharacterID, pilot.CharacterName, format(pilot.Value)))
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, "-")
+	}
+
+	return models.SlackField{
+		Title: title,
+		Value: strings.Join(lines, "\n"),
+		Short: true,
+	}
+}
+
+// CorporationName returns the name of the given corporation, falling back to its EVE corporation ID if no name was set
+func CorporationName(corporation *models.Corporation) string {
+	if len(corporation.Name) > 0 {
+		return corporation.Name
+	}
+
+	return fmt.Sprintf("#%d", corporation.EVECorporationID)
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// Status represents the current health of the parser
+type Status struct {
+	StartedAt             time.Time           `json:"startedAt"`
+	LastUpdate            time.Time           `json:"lastUpdate"`
+	LastHeartbeat         time.Time           `json:"lastHeartbeat"`
+	LastError             string              `json:"lastError"`
+	LastErrorAt           time.Time           `json:"lastErrorAt"`
+	LastZKillboardError   string              `json:"lastZKillboardError"`
+	LastZKillboardErrorAt time.Time           `json:"lastZKillboardErrorAt"`
+	LastSlackError        string              `json:"lastSlackError"`
+	LastSlackErrorAt      time.Time           `json:"lastSlackErrorAt"`
+	LookupError           string              `json:"lookupError"`
+	LookupCheckedAt       time.Time           `json:"lookupCheckedAt"`
+	Corporations          int                 `json:"corporations"`
+	CorporationUpdates    map[int64]time.Time `json:"corporationUpdates"`
+	RetryQueueSize        int                 `json:"retryQueueSize"`
+}
+
+// Status returns a snapshot of the current health of the parser
+func (parser *Parser) Status() Status {
+	parser.stateMutex.RLock()
+	defer parser.stateMutex.RUnlock()
+
+	status := parser.status
+	status.RetryQueueSize = len(parser.retryQueue)
+
+	status.CorporationUpdates = make(map[int64]time.Time, len(parser.status.CorporationUpdates))
+	for corporationID, lastUpdate := range parser.status.CorporationUpdates {
+		status.CorporationUpdates[corporationID] = lastUpdate
+	}
+
+	status.LastZKillboardError, status.LastZKillboardErrorAt = parser.zkillboard.LastError()
+	status.LastSlackError, status.LastSlackErrorAt = parser.slackClient.LastError()
+
+	return status
+}
+
+// UpdateInterval returns the interval between two regular update cycles
+func (parser *Parser) UpdateInterval() time.Duration {
+	return parser.interval
+}
+
+// UpdateAll runs an update for every tracked corporation, recording the outcome in the parser's status
+func (parser *Parser) UpdateAll() {
+	parser.stateMutex.Lock()
+	parser.cycleID++
+	updates := make(map[int64]time.Time, len(parser.Corporations))
+	for _, corporation := range parser.Corporations {
+		updates[corporation.EVECorporationID] = parser.status.CorporationUpdates[corporation.EVECorporationID]
+	}
+	parser.status.Corporations = len(parser.Corporations)
+	parser.status.CorporationUpdates = updates
+	parser.stateMutex.Unlock()
+
+	for _, corporation := range parser.Corporations {
+		parser.heartbeat()
+
+		err := parser.Update(corporation)
+		if err != nil {
+			parser.logger(corporation).Errorf("Received error while updating corporation #%d: [%v]", corporation.EVECorporationID, err)
+			parser.recordError(corporation, err)
+			continue
+		}
+
+		parser.recordUpdate(corporation)
+	}
+
+	parser.stateMutex.Lock()
+	parser.status.LastUpdate = time.Now()
+	parser.status.LastHeartbeat = parser.status.LastUpdate
+	parser.stateMutex.Unlock()
+}
+
+// heartbeat records that the update loop is still making progress
+func (parser *Parser) heartbeat() {
+	parser.stateMutex.Lock()
+	defer parser.stateMutex.Unlock()
+
+	parser.status.LastHeartbeat = time.Now()
+}
+
+// recordUpdate stores the time of the successful update of the given corporation as soon as it finished
+func (parser *Parser) recordUpdate(corporation *models.Corporation) {
+	parser.stateMutex.Lock()
+	defer parser.stateMutex.Unlock()
+
+	parser.status.CorporationUpdates[corporation.EVECorporationID] = time.Now()
+	parser.status.LastHeartbeat = time.Now()
+}
+
+// recordLookup stores the outcome of the most recent server version refresh of the lookup service
+func (parser *Parser) recordLookup(err error) {
+	parser.stateMutex.Lock()
+	defer parser.stateMutex.Unlock()
+
+	parser.status.LookupError = ""
+	if err != nil {
+		parser.status.LookupError = err.Error()
+	}
+	parser.status.LookupCheckedAt = time.Now()
+}
+
+// recordError stores the given error as the most recent error encountered by the parser
+func (parser *Parser) recordError(corporation *models.Corporation, err error) {
+	parser.stateMutex.Lock()
+	defer parser.stateMutex.Unlock()
+
+	parser.status.LastError = CorporationName(corporation) + ": " + err.Error()
+	parser.status.LastErrorAt = time.Now()
+}
+
+// Prices returns the price source used for valuing kills and losses, nil if the values provided by zKillboard are used
+func (parser *Parser) Prices() models.PriceSource {
+	if parser.prices == nil {
+		return nil
+	}
+
+	return parser.prices
+}
+
+// ItemTypeName returns the name of the given item type, falling back to its ID if the lookup failed
+func (parser *Parser) ItemTypeName(typeID int64) string {
+	itemType, err := parser.lookup.FetchItemType(misc.Logger, typeID)
+	if err != nil {
+		misc.Logger.Warnf("Failed to query item type ID #%d: [%v]", typeID, err)
+		return fmt.Sprintf("#%d", typeID)
+	}
+
+	return itemType.Name
+}
+
+// SolarSystemName returns the name of the given solar system, falling back to its ID if the lookup failed
+func (parser *Parser) SolarSystemName(solarSystemID int64) string {
+	locationInfo, err := parser.lookup.FetchLocationInfo(misc.Logger, solarSystemID)
+	if err != nil {
+		misc.Logger.Warnf("Failed to query location info for solar system ID #%d: [%v]", solarSystemID, err)
+		return fmt.Sprintf("#%d", solarSystemID)
+	}
+
+	return locationInfo.SolarSystemName
+}
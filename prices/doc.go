@@ -0,0 +1,3 @@
+// Package prices provides item prices used by the application to value kills and losses independently of zKillboard.
+// While presenting a high-level interface to the rest of the application, the package can use different price sources, such as a local price table or an HTTP source.
+package prices
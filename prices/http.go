@@ -0,0 +1,105 @@
+package prices
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// HTTPProvider provides item prices retrieved from an HTTP source in the ESI market prices format, caching them until the next refresh. HTTPProvider is safe for concurrent use
+type HTTPProvider struct {
+	url    string
+	client *http.Client
+	mutex  sync.RWMutex
+	etag   string
+	prices map[int64]float64
+}
+
+// NewHTTPProvider creates a new HTTPProvider retrieving prices from the given URL. Prices are only available after the first refresh
+func NewHTTPProvider(url string) *HTTPProvider {
+	p := &HTTPProvider{
+		url:    url,
+		client: &http.Client{Timeout: time.Second * 30},
+		prices: make(map[int64]float64),
+	}
+
+	return p
+}
+
+// ItemPrice returns the price of a single item of the given type, returning false if no price is known
+func (p *HTTPProvider) ItemPrice(typeID int64) (float64, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	price, ok := p.prices[typeID]
+	return price, ok
+}
+
+// Refresh retrieves the current prices, keeping the previous prices if the request failed or the source has not been modified
+func (p *HTTPProvider) Refresh() error {
+	req, err := http.NewRequest("GET", p.url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "eveslackkills github.com/morpheusxaut/eveslackkills")
+
+	p.mutex.RLock()
+	if len(p.etag) > 0 {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	p.mutex.RUnlock()
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		misc.Logger.Tracef("Item prices at %q not modified", p.url)
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Received non-OK HTTP status code %s (%d)", resp.Status, resp.StatusCode)
+	}
+
+	response, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var marketPrices []models.ESIMarketPrice
+
+	err = json.Unmarshal(response, &marketPrices)
+	if err != nil {
+		return err
+	}
+
+	prices := make(map[int64]float64, len(marketPrices))
+	for _, marketPrice := range marketPrices {
+		if marketPrice.AveragePrice > 0 {
+			prices[marketPrice.TypeID] = marketPrice.AveragePrice
+		} else {
+			prices[marketPrice.TypeID] = marketPrice.AdjustedPrice
+		}
+	}
+
+	p.mutex.Lock()
+	p.prices = prices
+	p.etag = resp.Header.Get("ETag")
+	p.mutex.Unlock()
+
+	misc.Logger.Debugf("Loaded %d item prices from %q", len(prices), p.url)
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package prices
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	// DefaultURL represents the default HTTP source for item prices, using the ESI market prices
+	DefaultURL = "https://esi.evetech.net/latest/markets/prices/"
+	// DefaultRefreshInterval represents the interval used to refresh prices if none was configured
+	DefaultRefreshInterval = time.Hour * 6
+)
+
+// Provider provides an interface for retrieving item prices from a refreshable source
+type Provider interface {
+	models.PriceSource
+
+	// Refresh reloads all prices from the underlying source, keeping the previous prices if an error occurred
+	Refresh() error
+}
+
+// SetupProvider parses the price source type set in the configuration and returns an appropriate provider with loaded prices, nil if zKillboard values should be used or an error if the type is unknown.
+// If the prices could not be loaded, the provider is returned without prices until the next successful refresh
+func SetupProvider(conf *misc.Configuration) (Provider, error) {
+	var provider Provider
+
+	switch Type(conf.PriceSourceType) {
+	case TypeNone:
+		// Configurations predating the price source type only set a price table
+		if len(conf.PriceTableFile) == 0 {
+			return nil, nil
+		}
+
+		provider = NewTable(conf.PriceTableFile)
+		break
+	case TypeTable:
+		provider = NewTable(conf.PriceTableFile)
+		break
+	case TypeHTTP:
+		url := conf.PriceURL
+		if len(url) == 0 {
+			url = DefaultURL
+		}
+
+		provider = NewHTTPProvider(url)
+		break
+	default:
+		return nil, fmt.Errorf("Unknown type #%d", conf.PriceSourceType)
+	}
+
+	err := provider.Refresh()
+	if err != nil {
+		misc.Logger.Warnf("Failed to load item prices, using zKillboard values until the next refresh: [%v]", err)
+	}
+
+	return provider, nil
+}
+
+// RefreshPeriodically refreshes the prices of the given provider at the provided interval, using the default for non-positive values. This function blocks and should be run in a separate goroutine
+func RefreshPeriodically(provider Provider, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+
+	ticker := time.NewTicker(interval)
+
+	for range ticker.C {
+		misc.Logger.Debugf("Refreshing item prices")
+
+		err := provider.Refresh()
+		if err != nil {
+			misc.Logger.Warnf("Failed to refresh item prices: [%v]", err)
+		}
+	}
+}
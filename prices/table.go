@@ -0,0 +1,134 @@
+package prices
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+)
+
+// Table provides item prices read from a local price table, either stored as a JSON object mapping type IDs to prices or as a CSV file with type ID and price columns. Table is safe for concurrent use
+type Table struct {
+	path   string
+	mutex  sync.RWMutex
+	prices map[int64]float64
+}
+
+// NewTable creates a new Table reading prices from the given path. Prices are only available after the first refresh
+func NewTable(path string) *Table {
+	t := &Table{
+		path:   path,
+		prices: make(map[int64]float64),
+	}
+
+	return t
+}
+
+// ItemPrice returns the price of a single item of the given type, returning false if the table contains no price for it
+func (t *Table) ItemPrice(typeID int64) (float64, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	price, ok := t.prices[typeID]
+	return price, ok
+}
+
+// Refresh reloads the price table from disk, keeping the previous prices if the file could not be parsed
+func (t *Table) Refresh() error {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var prices map[int64]float64
+
+	if strings.EqualFold(filepath.Ext(t.path), ".csv") {
+		prices, err = parseCSV(file)
+	} else {
+		prices, err = parseJSON(file)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	t.prices = prices
+	t.mutex.Unlock()
+
+	misc.Logger.Debugf("Loaded %d item prices from %q", len(prices), t.path)
+
+	return nil
+}
+
+// parseJSON parses a JSON object mapping type IDs to prices
+func parseJSON(r io.Reader) (map[int64]float64, error) {
+	var raw map[string]float64
+
+	err := json.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[int64]float64, len(raw))
+	for key, price := range raw {
+		typeID, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid type ID %q", key)
+		}
+
+		prices[typeID] = price
+	}
+
+	return prices, nil
+}
+
+// parseCSV parses a CSV file containing a type ID and price per line, skipping a header line if present
+func parseCSV(r io.Reader) (map[int64]float64, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	prices := make(map[int64]float64)
+	line := 0
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		line++
+
+		if len(record) < 2 {
+			return nil, fmt.Errorf("Invalid price table entry in line %d", line)
+		}
+
+		typeID, err := strconv.ParseInt(strings.TrimSpace(record[0]), 10, 64)
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+
+			return nil, fmt.Errorf("Invalid type ID %q in line %d", record[0], line)
+		}
+
+		price, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid price %q in line %d", record[1], line)
+		}
+
+		prices[typeID] = price
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,27 @@
+package prices
+
+// Type represents the type of price provider to use
+type Type int
+
+const (
+	// TypeNone represents using the values provided by zKillboard instead of a price provider
+	TypeNone Type = iota
+	// TypeTable represents a local CSV or JSON price table
+	TypeTable
+	// TypeHTTP represents prices retrieved via HTTP, by default from the ESI market prices
+	TypeHTTP
+)
+
+// String returns a easily readable string representations of the given Type
+func (t Type) String() string {
+	switch t {
+	case TypeNone:
+		return "None"
+	case TypeTable:
+		return "Table"
+	case TypeHTTP:
+		return "HTTP"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,178 @@
+package reports
+
+import (
+	"sort"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// PilotStatistic represents a single pilot and a value used for ranking
+type PilotStatistic struct {
+	CharacterID   int64
+	CharacterName string
+	Value         float64
+}
+
+// SystemStatistic represents a single solar system and the number of kills and losses within it
+type SystemStatistic struct {
+	SolarSystemID int64
+	Count         int
+}
+
+// Digest represents aggregated statistics about the kills and losses of a corporation within a period
+type Digest struct {
+	Corporation            *models.Corporation
+	From                   time.Time
+	To                     time.Time
+	Kills                  int
+	Losses                 int
+	ISKDestroyed           float64
+	ISKLost                float64
+	TopKillers             []PilotStatistic
+	MostExpensiveLoss      *models.Killmail
+	MostExpensiveLossValue float64
+	BusiestSystems         []SystemStatistic
+}
+
+// Efficiency returns the share of ISK destroyed in relation to the total ISK involved, in percent
+func (d *Digest) Efficiency() float64 {
+	if d.ISKDestroyed+d.ISKLost <= 0 {
+		return 0
+	}
+
+	return d.ISKDestroyed / (d.ISKDestroyed + d.ISKLost) * 100
+}
+
+// GenerateDigest aggregates the given killmails of the corporation into a digest, listing up to limit top killers and solar systems.
+// Kills and losses are valued using the given price source if available, falling back to the values provided by zKillboard
+func GenerateDigest(corporation *models.Corporation, killmails []*models.Killmail, from time.Time, to time.Time, limit int, prices models.PriceSource) *Digest {
+	d := &Digest{
+		Corporation: corporation,
+		From:        from,
+		To:          to,
+	}
+
+	killers := make(map[int64]*PilotStatistic)
+	systems := make(map[int64]*SystemStatistic)
+
+	for _, killmail := range killmails {
+		system, ok := systems[killmail.SolarSystemID]
+		if !ok {
+			system = &SystemStatistic{
+				SolarSystemID: killmail.SolarSystemID,
+			}
+			systems[killmail.SolarSystemID] = system
+		}
+
+		system.Count++
+
+		value := killmail.Entry.Value(prices)
+
+		switch killmail.Type {
+		case models.ZKillboardEntryTypeKill:
+			d.Kills++
+			d.ISKDestroyed += value
+
+			for _, attacker := range killmail.Entry.Attackers {
+				if attacker.CharacterID == 0 || attacker.CorporationID != corporation.EVECorporationID {
+					continue
+				}
+
+				killer, ok := killers[attacker.CharacterID]
+				if !ok {
+					killer = &PilotStatistic{
+						CharacterID:   attacker.CharacterID,
+						CharacterName: attacker.CharacterName,
+					}
+					killers[attacker.CharacterID] = killer
+				}
+
+				killer.Value++
+			}
+		case models.ZKillboardEntryTypeLoss:
+			d.Losses++
+			d.ISKLost += value
+
+			if d.MostExpensiveLoss == nil || value > d.MostExpensiveLossValue {
+				d.MostExpensiveLoss = killmail
+				d.MostExpensiveLossValue = value
+			}
+		}
+	}
+
+	d.TopKillers = RankPilots(killers, limit)
+
+	d.BusiestSystems = make([]SystemStatistic, 0, len(systems))
+	for _, system := range systems {
+		d.BusiestSystems = append(d.BusiestSystems, *system)
+	}
+
+	sort.Sort(BySystemCount(d.BusiestSystems))
+
+	if limit > 0 && len(d.BusiestSystems) > limit {
+		d.BusiestSystems = d.BusiestSystems[:limit]
+	}
+
+	return d
+}
+
+// RankPilots sorts the given pilot statistics by value, returning up to limit entries
+func RankPilots(pilots map[int64]*PilotStatistic, limit int) []PilotStatistic {
+	ranking := make([]PilotStatistic, 0, len(pilots))
+	for _, pilot := range pilots {
+		ranking = append(ranking, *pilot)
+	}
+
+	sort.Sort(ByPilotValue(ranking))
+
+	if limit > 0 && len(ranking) > limit {
+		ranking = ranking[:limit]
+	}
+
+	return ranking
+}
+
+// ByPilotValue represents an array of pilot statistics, used for sorting by descending value
+type ByPilotValue []PilotStatistic
+
+// Len returns the length of the array of pilot statistics to sort
+func (p ByPilotValue) Len() int {
+	return len(p)
+}
+
+// Swap swaps two entries in the array of pilot statistics to sort
+func (p ByPilotValue) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
+
+// Less is used for sorting the array of pilot statistics by descending value, falling back to character IDs for identical values
+func (p ByPilotValue) Less(i, j int) bool {
+	if p[i].Value != p[j].Value {
+		return p[i].Value > p[j].Value
+	}
+
+	return p[i].CharacterID < p[j].CharacterID
+}
+
+// BySystemCount represents an array of solar system statistics, used for sorting by descending count
+type BySystemCount []SystemStatistic
+
+// Len returns the length of the array of solar system statistics to sort
+func (s BySystemCount) Len() int {
+	return len(s)
+}
+
+// Swap swaps two entries in the array of solar system statistics to sort
+func (s BySystemCount) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less is used for sorting the array of solar system statistics by descending count, falling back to solar system IDs for identical counts
+func (s BySystemCount) Less(i, j int) bool {
+	if s[i].Count != s[j].Count {
+		return s[i].Count > s[j].Count
+	}
+
+	return s[i].SolarSystemID < s[j].SolarSystemID
+}
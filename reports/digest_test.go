@@ -0,0 +1,119 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	testCorporationID = 98388312
+)
+
+// testKillmail creates a killmail of the given type and value in the given solar system, listing the provided attackers
+func testKillmail(entryType models.ZKillboardEntryType, killID int64, solarSystemID int64, value float64, attackers ...models.ZKillboardAttacker) *models.Killmail {
+	return &models.Killmail{
+		CorporationID: 1,
+		KillID:        killID,
+		Type:          entryType,
+		KillTime:      time.Date(2016, 8, 21, 18, 0, 0, 0, time.UTC),
+		SolarSystemID: solarSystemID,
+		TotalValue:    value,
+		Entry: models.ZKillboardEntry{
+			KillID:        killID,
+			SolarSystemID: solarSystemID,
+			Attackers:     attackers,
+			Misc:          models.ZKillboardMiscellaneous{TotalValue: value},
+		},
+	}
+}
+
+// testAttacker creates an attacker with the given character and corporation ID
+func testAttacker(characterID int64, corporationID int64) models.ZKillboardAttacker {
+	return models.ZKillboardAttacker{
+		CharacterID:   characterID,
+		CharacterName: "Pilot",
+		CorporationID: corporationID,
+	}
+}
+
+func TestGenerateDigest(t *testing.T) {
+	corporation := &models.Corporation{EVECorporationID: testCorporationID}
+
+	killmails := []*models.Killmail{
+		testKillmail(models.ZKillboardEntryTypeKill, 1, 30000142, 100, testAttacker(1, testCorporationID), testAttacker(2, testCorporationID), testAttacker(9, 98000001)),
+		testKillmail(models.ZKillboardEntryTypeKill, 2, 30000142, 300, testAttacker(1, testCorporationID)),
+		testKillmail(models.ZKillboardEntryTypeKill, 3, 30002187, 200, testAttacker(3, testCorporationID), testAttacker(0, testCorporationID)),
+		testKillmail(models.ZKillboardEntryTypeLoss, 4, 30002187, 50, testAttacker(9, 98000001)),
+		testKillmail(models.ZKillboardEntryTypeLoss, 5, 30000142, 150, testAttacker(9, 98000001)),
+	}
+
+	d := GenerateDigest(corporation, killmails, time.Time{}, time.Time{}, 2, nil)
+
+	if d.Kills != 3 || d.Losses != 2 {
+		t.Errorf("Expected 3 kills and 2 losses, got %d and %d", d.Kills, d.Losses)
+	}
+	if d.ISKDestroyed != 600 || d.ISKLost != 200 {
+		t.Errorf("Expected 600 ISK destroyed and 200 ISK lost, got %.0f and %.0f", d.ISKDestroyed, d.ISKLost)
+	}
+	if d.Efficiency() != 75 {
+		t.Errorf("Expected efficiency of 75%%, got %.2f%%", d.Efficiency())
+	}
+
+	if d.MostExpensiveLoss == nil || d.MostExpensiveLoss.KillID != 5 || d.MostExpensiveLossValue != 150 {
+		t.Errorf("Expected loss #5 worth 150 ISK to be the most expensive loss, got %v", d.MostExpensiveLoss)
+	}
+
+	if len(d.TopKillers) != 2 || d.TopKillers[0].CharacterID != 1 || d.TopKillers[0].Value != 2 || d.TopKillers[1].CharacterID != 2 {
+		t.Errorf("Expected top killers #1 (2 kills) and #2 (1 kill), got %v", d.TopKillers)
+	}
+
+	if len(d.BusiestSystems) != 2 || d.BusiestSystems[0].SolarSystemID != 30000142 || d.BusiestSystems[0].Count != 3 || d.BusiestSystems[1].Count != 2 {
+		t.Errorf("Expected busiest systems #30000142 (3) and #30002187 (2), got %v", d.BusiestSystems)
+	}
+}
+
+func TestGenerateDigestEmpty(t *testing.T) {
+	d := GenerateDigest(&models.Corporation{EVECorporationID: testCorporationID}, nil, time.Time{}, time.Time{}, 5, nil)
+
+	if d.Kills != 0 || d.Losses != 0 || d.MostExpensiveLoss != nil || len(d.TopKillers) != 0 || len(d.BusiestSystems) != 0 {
+		t.Errorf("Expected empty digest, got %+v", d)
+	}
+	if d.Efficiency() != 0 {
+		t.Errorf("Expected efficiency of 0%% without any kills, got %.2f%%", d.Efficiency())
+	}
+}
+
+func TestRankPilots(t *testing.T) {
+	pilots := map[int64]*PilotStatistic{
+		3: {CharacterID: 3, Value: 5},
+		1: {CharacterID: 1, Value: 5},
+		2: {CharacterID: 2, Value: 10},
+		4: {CharacterID: 4, Value: 1},
+	}
+
+	tests := []struct {
+		limit int
+		ids   []int64
+	}{
+		{limit: 0, ids: []int64{2, 1, 3, 4}},
+		{limit: 2, ids: []int64{2, 1}},
+		{limit: 10, ids: []int64{2, 1, 3, 4}},
+	}
+
+	for _, test := range tests {
+		ranking := RankPilots(pilots, test.limit)
+
+		if len(ranking) != len(test.ids) {
+			t.Errorf("Limit %d: expected %d pilots, got %d", test.limit, len(test.ids), len(ranking))
+			continue
+		}
+
+		for i, id := range test.ids {
+			if ranking[i].CharacterID != id {
+				t.Errorf("Limit %d: expected pilot #%d at rank %d, got #%d", test.limit, id, i+1, ranking[i].CharacterID)
+			}
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package reports provides the generation of aggregated statistics from the stored killmail history, used by the application for scheduled reports.
+package reports
@@ -0,0 +1,81 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// Leaderboard represents rankings of the pilots of a corporation within a period
+type Leaderboard struct {
+	Corporation       *models.Corporation
+	From              time.Time
+	To                time.Time
+	FinalBlows        []PilotStatistic
+	DamageDone        []PilotStatistic
+	KillsParticipated []PilotStatistic
+	ISKDestroyed      []PilotStatistic
+	SoloKills         []PilotStatistic
+}
+
+// GenerateLeaderboard ranks the pilots of the corporation involved in the given kills, listing up to limit pilots per category.
+// Kills are valued using the given price source if available, falling back to the values provided by zKillboard
+func GenerateLeaderboard(corporation *models.Corporation, killmails []*models.Killmail, from time.Time, to time.Time, limit int, prices models.PriceSource) *Leaderboard {
+	finalBlows := make(map[int64]*PilotStatistic)
+	damageDone := make(map[int64]*PilotStatistic)
+	killsParticipated := make(map[int64]*PilotStatistic)
+	iskDestroyed := make(map[int64]*PilotStatistic)
+	soloKills := make(map[int64]*PilotStatistic)
+
+	for _, killmail := range killmails {
+		if killmail.Type != models.ZKillboardEntryTypeKill {
+			continue
+		}
+
+		value := killmail.Entry.Value(prices)
+
+		for _, attacker := range killmail.Entry.Attackers {
+			if attacker.CharacterID == 0 || attacker.CorporationID != corporation.EVECorporationID {
+				continue
+			}
+
+			if attacker.FinalBlow == 1 {
+				addPilotValue(finalBlows, attacker, 1)
+			}
+			if killmail.Entry.Misc.Solo {
+				addPilotValue(soloKills, attacker, 1)
+			}
+
+			addPilotValue(damageDone, attacker, float64(attacker.DamageDone))
+			addPilotValue(killsParticipated, attacker, 1)
+			addPilotValue(iskDestroyed, attacker, value)
+		}
+	}
+
+	l := &Leaderboard{
+		Corporation:       corporation,
+		From:              from,
+		To:                to,
+		FinalBlows:        RankPilots(finalBlows, limit),
+		DamageDone:        RankPilots(damageDone, limit),
+		KillsParticipated: RankPilots(killsParticipated, limit),
+		ISKDestroyed:      RankPilots(iskDestroyed, limit),
+		SoloKills:         RankPilots(soloKills, limit),
+	}
+
+	return l
+}
+
+// addPilotValue adds the given value to the statistic of the attacker, creating a new entry if required
+func addPilotValue(pilots map[int64]*PilotStatistic, attacker models.ZKillboardAttacker, value float64) {
+	pilot, ok := pilots[attacker.CharacterID]
+	if !ok {
+		pilot = &PilotStatistic{
+			CharacterID:   attacker.CharacterID,
+			CharacterName: attacker.CharacterName,
+		}
+		pilots[attacker.CharacterID] = pilot
+	}
+
+	pilot.Value += value
+}
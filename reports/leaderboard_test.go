@@ -0,0 +1,91 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+func TestGenerateLeaderboard(t *testing.T) {
+	corporation := &models.Corporation{EVECorporationID: testCorporationID}
+
+	first := testKillmail(models.ZKillboardEntryTypeKill, 1, 30000142, 100, testAttacker(1, testCorporationID), testAttacker(2, testCorporationID))
+	first.Entry.Attackers[0].DamageDone = 300
+	first.Entry.Attackers[1].DamageDone = 700
+	first.Entry.Attackers[1].FinalBlow = 1
+
+	solo := testKillmail(models.ZKillboardEntryTypeKill, 2, 30000142, 500, testAttacker(1, testCorporationID))
+	solo.Entry.Attackers[0].DamageDone = 1000
+	solo.Entry.Attackers[0].FinalBlow = 1
+	solo.Entry.Misc.Solo = true
+
+	npcAssisted := testKillmail(models.ZKillboardEntryTypeKill, 5, 30000142, 200, testAttacker(2, testCorporationID), testAttacker(0, 1000125))
+	npcAssisted.Entry.Attackers[0].DamageDone = 400
+	npcAssisted.Entry.Attackers[1].FinalBlow = 1
+	npcAssisted.Entry.Misc.Solo = false
+
+	foreign := testKillmail(models.ZKillboardEntryTypeKill, 3, 30000142, 50, testAttacker(9, 98000001))
+	foreign.Entry.Attackers[0].FinalBlow = 1
+
+	loss := testKillmail(models.ZKillboardEntryTypeLoss, 4, 30000142, 1000, testAttacker(9, 98000001))
+
+	l := GenerateLeaderboard(corporation, []*models.Killmail{first, solo, npcAssisted, foreign, loss}, time.Time{}, time.Time{}, 5, nil)
+
+	tests := []struct {
+		category string
+		ranking  []PilotStatistic
+		expected []PilotStatistic
+	}{
+		{
+			category: "final blows",
+			ranking:  l.FinalBlows,
+			expected: []PilotStatistic{{CharacterID: 1, Value: 1}, {CharacterID: 2, Value: 1}},
+		},
+		{
+			category: "damage done",
+			ranking:  l.DamageDone,
+			expected: []PilotStatistic{{CharacterID: 1, Value: 1300}, {CharacterID: 2, Value: 1100}},
+		},
+		{
+			category: "kills participated",
+			ranking:  l.KillsParticipated,
+			expected: []PilotStatistic{{CharacterID: 1, Value: 2}, {CharacterID: 2, Value: 2}},
+		},
+		{
+			category: "ISK destroyed",
+			ranking:  l.ISKDestroyed,
+			expected: []PilotStatistic{{CharacterID: 1, Value: 600}, {CharacterID: 2, Value: 300}},
+		},
+		{
+			category: "solo kills",
+			ranking:  l.SoloKills,
+			expected: []PilotStatistic{{CharacterID: 1, Value: 1}},
+		},
+	}
+
+	for _, test := range tests {
+		if len(test.ranking) != len(test.expected) {
+			t.Errorf("%s: expected %d pilots, got %v", test.category, len(test.expected), test.ranking)
+			continue
+		}
+
+		for i, expected := range test.expected {
+			if test.ranking[i].CharacterID != expected.CharacterID || test.ranking[i].Value != expected.Value {
+				t.Errorf("%s: expected pilot #%d with %.0f at rank %d, got #%d with %.0f", test.category, expected.CharacterID, expected.Value, i+1, test.ranking[i].CharacterID, test.ranking[i].Value)
+			}
+		}
+	}
+}
+
+func TestGenerateLeaderboardLimit(t *testing.T) {
+	corporation := &models.Corporation{EVECorporationID: testCorporationID}
+
+	killmail := testKillmail(models.ZKillboardEntryTypeKill, 1, 30000142, 100, testAttacker(1, testCorporationID), testAttacker(2, testCorporationID), testAttacker(3, testCorporationID))
+
+	l := GenerateLeaderboard(corporation, []*models.Killmail{killmail}, time.Time{}, time.Time{}, 2, nil)
+
+	if len(l.KillsParticipated) != 2 {
+		t.Errorf("Expected ranking to be limited to 2 pilots, got %d", len(l.KillsParticipated))
+	}
+}
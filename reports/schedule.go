@@ -0,0 +1,159 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kind represents the kind of report generated by a schedule
+type Kind int
+
+const (
+	// KindDigest represents a summary of kills and losses
+	KindDigest Kind = iota
+	// KindLeaderboard represents a ranking of the corporation's pilots
+	KindLeaderboard
+)
+
+// String returns a easily readable string representations of the given Kind
+func (k Kind) String() string {
+	switch k {
+	case KindDigest:
+		return "digest"
+	case KindLeaderboard:
+		return "leaderboard"
+	default:
+		return "unknown"
+	}
+}
+
+// Interval represents the recurrence of a scheduled report
+type Interval int
+
+const (
+	// IntervalDaily represents a report covering the previous day
+	IntervalDaily Interval = iota
+	// IntervalWeekly represents a report covering the previous week, starting on Monday
+	IntervalWeekly
+	// IntervalMonthly represents a report covering the previous calendar month
+	IntervalMonthly
+)
+
+// ParseInterval parses the given interval name (daily, weekly or monthly), returning an error if the name is unknown
+func ParseInterval(name string) (Interval, error) {
+	switch strings.ToLower(name) {
+	case "daily":
+		return IntervalDaily, nil
+	case "weekly":
+		return IntervalWeekly, nil
+	case "monthly":
+		return IntervalMonthly, nil
+	default:
+		return IntervalDaily, fmt.Errorf("Unknown interval %q", name)
+	}
+}
+
+// String returns a easily readable string representations of the given Interval
+func (i Interval) String() string {
+	switch i {
+	case IntervalDaily:
+		return "Daily"
+	case IntervalWeekly:
+		return "Weekly"
+	case IntervalMonthly:
+		return "Monthly"
+	default:
+		return "Unknown"
+	}
+}
+
+// Schedule represents a recurring report, running at a given hour (EVE time) after the end of each interval
+type Schedule struct {
+	Kind     Kind
+	Interval Interval
+	Hour     int
+	Next     time.Time
+}
+
+// NewSchedule creates a new Schedule for the given kind of report, interval and hour, calculating the next run after the provided time
+func NewSchedule(kind Kind, interval Interval, hour int, now time.Time) *Schedule {
+	s := &Schedule{
+		Kind:     kind,
+		Interval: interval,
+		Hour:     hour,
+	}
+
+	s.Next = s.nextRun(now)
+
+	return s
+}
+
+// Due checks whether the next run of the schedule has been reached
+func (s *Schedule) Due(now time.Time) bool {
+	return !now.Before(s.Next)
+}
+
+// Period returns the start and end of the interval covered by the next run of the schedule
+func (s *Schedule) Period() (time.Time, time.Time) {
+	to := s.boundary(s.Next)
+
+	return s.previousBoundary(to), to
+}
+
+// Advance calculates the following run of the schedule after the given time
+func (s *Schedule) Advance(now time.Time) {
+	s.Next = s.nextRun(now)
+}
+
+// nextRun returns the first run of the schedule after the given time
+func (s *Schedule) nextRun(after time.Time) time.Time {
+	boundary := s.boundary(after)
+
+	run := boundary.Add(time.Hour * time.Duration(s.Hour))
+	if !run.After(after) {
+		run = s.nextBoundary(boundary).Add(time.Hour * time.Duration(s.Hour))
+	}
+
+	return run
+}
+
+// boundary returns the start of the interval containing the given time
+func (s *Schedule) boundary(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch s.Interval {
+	case IntervalWeekly:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case IntervalMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// nextBoundary returns the start of the interval following the one starting at the given boundary
+func (s *Schedule) nextBoundary(boundary time.Time) time.Time {
+	switch s.Interval {
+	case IntervalWeekly:
+		return boundary.AddDate(0, 0, 7)
+	case IntervalMonthly:
+		return boundary.AddDate(0, 1, 0)
+	default:
+		return boundary.AddDate(0, 0, 1)
+	}
+}
+
+// previousBoundary returns the start of the interval preceding the one starting at the given boundary
+func (s *Schedule) previousBoundary(boundary time.Time) time.Time {
+	switch s.Interval {
+	case IntervalWeekly:
+		return boundary.AddDate(0, 0, -7)
+	case IntervalMonthly:
+		return boundary.AddDate(0, -1, 0)
+	default:
+		return boundary.AddDate(0, 0, -1)
+	}
+}
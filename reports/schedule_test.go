@@ -0,0 +1,94 @@
+package reports
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNextRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval Interval
+		hour     int
+		now      time.Time
+		next     time.Time
+		from     time.Time
+		to       time.Time
+	}{
+		{
+			name:     "daily before hour",
+			interval: IntervalDaily,
+			hour:     9,
+			now:      time.Date(2016, 8, 21, 8, 0, 0, 0, time.UTC),
+			next:     time.Date(2016, 8, 21, 9, 0, 0, 0, time.UTC),
+			from:     time.Date(2016, 8, 20, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2016, 8, 21, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily after hour",
+			interval: IntervalDaily,
+			hour:     9,
+			now:      time.Date(2016, 8, 21, 9, 0, 0, 0, time.UTC),
+			next:     time.Date(2016, 8, 22, 9, 0, 0, 0, time.UTC),
+			from:     time.Date(2016, 8, 21, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2016, 8, 22, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly",
+			interval: IntervalWeekly,
+			hour:     9,
+			now:      time.Date(2016, 8, 21, 12, 0, 0, 0, time.UTC),
+			next:     time.Date(2016, 8, 22, 9, 0, 0, 0, time.UTC),
+			from:     time.Date(2016, 8, 15, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2016, 8, 22, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "monthly",
+			interval: IntervalMonthly,
+			hour:     0,
+			now:      time.Date(2016, 12, 15, 0, 0, 0, 0, time.UTC),
+			next:     time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+			from:     time.Date(2016, 12, 1, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		schedule := NewSchedule(KindDigest, test.interval, test.hour, test.now)
+
+		if !schedule.Next.Equal(test.next) {
+			t.Errorf("%s: expected next run at %v, got %v", test.name, test.next, schedule.Next)
+		}
+
+		from, to := schedule.Period()
+		if !from.Equal(test.from) || !to.Equal(test.to) {
+			t.Errorf("%s: expected period %v - %v, got %v - %v", test.name, test.from, test.to, from, to)
+		}
+
+		if schedule.Due(test.now) || !schedule.Due(test.next) {
+			t.Errorf("%s: expected schedule to be due at %v only", test.name, test.next)
+		}
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval Interval
+		valid    bool
+	}{
+		{name: "daily", interval: IntervalDaily, valid: true},
+		{name: "Weekly", interval: IntervalWeekly, valid: true},
+		{name: "MONTHLY", interval: IntervalMonthly, valid: true},
+		{name: "yearly", valid: false},
+	}
+
+	for _, test := range tests {
+		interval, err := ParseInterval(test.name)
+		if test.valid && (err != nil || interval != test.interval) {
+			t.Errorf("ParseInterval(%q): expected %v, got %v (%v)", test.name, test.interval, interval, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("ParseInterval(%q): expected error", test.name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// WeeklyTotal represents the ISK destroyed and lost by a corporation within a single week (starting Monday, EVE time)
+type WeeklyTotal struct {
+	Start        time.Time
+	Kills        int
+	Losses       int
+	ISKDestroyed float64
+	ISKLost      float64
+}
+
+// WeekStart returns the beginning of the week (Monday 00:00, EVE time) containing the given time
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// GenerateWeeklyTotals aggregates the given killmails into totals for the given number of weeks up to and including the week containing the provided time, oldest first.
+// Kills and losses are valued using the given price source if available, falling back to the values provided by zKillboard
+func GenerateWeeklyTotals(killmails []*models.Killmail, to time.Time, weeks int, prices models.PriceSource) []WeeklyTotal {
+	totals := make([]WeeklyTotal, weeks)
+
+	first := WeekStart(to).AddDate(0, 0, -7*(weeks-1))
+	for i := range totals {
+		totals[i].Start = first.AddDate(0, 0, 7*i)
+	}
+
+	for _, killmail := range killmails {
+		if killmail.KillTime.Before(first) {
+			continue
+		}
+
+		week := int(killmail.KillTime.Sub(first).Hours() / (24 * 7))
+		if week >= weeks {
+			continue
+		}
+
+		switch killmail.Type {
+		case models.ZKillboardEntryTypeKill:
+			totals[week].Kills++
+			totals[week].ISKDestroyed += killmail.Entry.Value(prices)
+		case models.ZKillboardEntryTypeLoss:
+			totals[week].Losses++
+			totals[week].ISKLost += killmail.Entry.Value(prices)
+		}
+	}
+
+	return totals
+}
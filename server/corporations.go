@@ -0,0 +1,219 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+// corporationRequest represents the settings of a corporation sent to the admin API, only applying the fields provided
+type corporationRequest struct {
+	EVECorporationID *int64   `json:"eveCorporationID"`
+	Name             *string  `json:"name"`
+	KillComment      *string  `json:"killComment"`
+	LossComment      *string  `json:"lossComment"`
+	AlertValue       *float64 `json:"alertValue"`
+	AlertStructures  *bool    `json:"alertStructures"`
+	AlertMention     *string  `json:"alertMention"`
+}
+
+// apply sets all provided fields of the request on the given corporation
+func (req *corporationRequest) apply(corporation *models.Corporation) {
+	if req.Name != nil {
+		corporation.Name = *req.Name
+	}
+	if req.KillComment != nil {
+		corporation.KillComment = *req.KillComment
+	}
+	if req.LossComment != nil {
+		corporation.LossComment = *req.LossComment
+	}
+	if req.AlertValue != nil {
+		corporation.AlertValue = *req.AlertValue
+	}
+	if req.AlertStructures != nil {
+		corporation.AlertStructures = *req.AlertStructures
+	}
+	if req.AlertMention != nil {
+		corporation.AlertMention = *req.AlertMention
+	}
+}
+
+// handleCorporations lists all tracked corporations or adds a new one
+func (server *Server) handleCorporations(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		corporations, err := server.database.LoadAllCorporations()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, corporations)
+	case "POST":
+		var req corporationRequest
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("Invalid request body: %v", err))
+			return
+		}
+
+		if req.EVECorporationID == nil || *req.EVECorporationID <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("Missing corporation ID"))
+			return
+		}
+
+		_, err = server.database.LoadCorporationByEVECorporationID(*req.EVECorporationID)
+		if err == nil {
+			writeError(w, http.StatusConflict, fmt.Errorf("Corporation #%d is already tracked", *req.EVECorporationID))
+			return
+		} else if err != sql.ErrNoRows {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		corporation := &models.Corporation{
+			EVECorporationID: *req.EVECorporationID,
+			KillComment:      models.DefaultKillComment,
+			LossComment:      models.DefaultLossComment,
+		}
+		req.apply(corporation)
+
+		err = server.database.UpdateCorporationSettings(corporation)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		misc.Logger.Infof("Added corporation #%d (%s) via admin API", corporation.EVECorporationID, corporation.Name)
+
+		server.parser.TriggerUpdate()
+
+		writeJSON(w, http.StatusCreated, corporation)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s not allowed", r.Method))
+	}
+}
+
+// handleCorporation retrieves, edits or removes a single corporation, delegating to the ignore list and alert ship group handlers for nested paths
+func (server *Server) handleCorporation(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/corporations/"), "/"), "/")
+
+	eveCorporationID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("Invalid corporation ID %q", parts[0]))
+		return
+	}
+
+	corporation, err := server.database.LoadCorporationByEVECorporationID(eveCorporationID)
+	if err == sql.ErrNoRows {
+		writeError(w, http.StatusNotFound, fmt.Errorf("Corporation #%d is not tracked", eveCorporationID))
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(parts) == 3 {
+		server.handleCorporationList(w, r, corporation, parts[1], parts[2])
+		return
+	} else if len(parts) != 1 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("Unknown path %q", r.URL.Path))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, corporation)
+	case "PUT", "PATCH":
+		var req corporationRequest
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("Invalid request body: %v", err))
+			return
+		}
+
+		req.apply(corporation)
+
+		corporation, err = server.database.SaveCorporation(corporation)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		misc.Logger.Infof("Updated corporation #%d via admin API", corporation.EVECorporationID)
+
+		server.parser.TriggerUpdate()
+
+		writeJSON(w, http.StatusOK, corporation)
+	case "DELETE":
+		err := server.database.DeleteCorporation(corporation.ID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		misc.Logger.Infof("Removed corporation #%d via admin API", corporation.EVECorporationID)
+
+		server.parser.TriggerUpdate()
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s not allowed", r.Method))
+	}
+}
+
+// handleCorporationList adds (PUT) or removes (DELETE) an entry of the ignored solar systems or alert ship groups of a corporation
+func (server *Server) handleCorporationList(w http.ResponseWriter, r *http.Request, corporation *models.Corporation, list string, value string) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("Invalid ID %q", value))
+		return
+	}
+
+	var add func(int64, int64) error
+	var remove func(int64, int64) error
+
+	switch list {
+	case "ignored":
+		add = server.database.AddIgnoredSolarSystem
+		remove = server.database.RemoveIgnoredSolarSystem
+		break
+	case "alertgroups":
+		add = server.database.AddAlertShipGroup
+		remove = server.database.RemoveAlertShipGroup
+		break
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("Unknown list %q", list))
+		return
+	}
+
+	switch r.Method {
+	case "PUT", "POST":
+		err = add(corporation.ID, id)
+	case "DELETE":
+		err = remove(corporation.ID, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s not allowed", r.Method))
+		return
+	}
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	misc.Logger.Infof("Updated %s list of corporation #%d via admin API", list, corporation.EVECorporationID)
+
+	server.parser.TriggerUpdate()
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,259 @@
+package server
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+	"github.com/morpheusxaut/eveslackkills/parser"
+	"github.com/morpheusxaut/eveslackkills/reports"
+)
+
+const (
+	// dashboardWeeks represents the number of weeks shown in the ISK chart of a corporation
+	dashboardWeeks = 8
+	// dashboardRecentSize represents the number of recent kills and losses listed for a corporation
+	dashboardRecentSize = 25
+	// dashboardRankingSize represents the number of top pilots listed for a corporation
+	dashboardRankingSize = 10
+	// dashboardBarWidth represents the width in pixels of the largest bar of the ISK chart
+	dashboardBarWidth = 300
+)
+
+// dashboardFunctions contains the helper functions available to all dashboard templates
+var dashboardFunctions = template.FuncMap{
+	"isk": func(value float64) string {
+		return fmt.Sprintf("%s ISK", humanize.Comma(int64(value)))
+	},
+	"evetime": func(t time.Time) string {
+		return t.UTC().Format(models.ZKillboardTimeFormat)
+	},
+}
+
+// dashboardCorporation represents a single corporation listed on the dashboard overview
+type dashboardCorporation struct {
+	Corporation *models.Corporation
+	Name        string
+	Digest      *reports.Digest
+}
+
+// dashboardWeek represents a single week of the ISK chart, including the bar widths
+type dashboardWeek struct {
+	reports.WeeklyTotal
+	DestroyedWidth float64
+	LostWidth      float64
+}
+
+// dashboardPilots represents a row of the top pilot rankings
+type dashboardPilots struct {
+	Kills *reports.PilotStatistic
+	ISK   *reports.PilotStatistic
+}
+
+// dashboardKillmail represents a single recent kill or loss
+type dashboardKillmail struct {
+	KillID   int64
+	Type     string
+	KillTime time.Time
+	Victim   string
+	ShipName string
+	Value    float64
+}
+
+// indexPage represents the data rendered by the dashboard overview
+type indexPage struct {
+	Title        string
+	Generated    time.Time
+	Corporations []dashboardCorporation
+	RecentPosts  []parser.RecentPost
+	Status       parser.Status
+}
+
+// corporationPage represents the data rendered by the dashboard page of a single corporation
+type corporationPage struct {
+	Title     string
+	Generated time.Time
+	Weeks     []dashboardWeek
+	TopPilots []dashboardPilots
+	Recent    []dashboardKillmail
+}
+
+// parseDashboardTemplates parses all dashboard templates, panicking if a template is invalid
+func parseDashboardTemplates() *template.Template {
+	templates := template.Must(template.New("layout").Funcs(dashboardFunctions).Parse(layoutTemplate))
+	template.Must(templates.New("index").Parse(indexTemplate))
+	template.Must(templates.New("corporation").Parse(corporationTemplate))
+
+	return templates
+}
+
+// handleDashboard renders the overview of all tracked corporations
+func (server *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	corporations, err := server.database.LoadAllCorporations()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -7)
+
+	page := &indexPage{
+		Title:        "Overview",
+		Generated:    to,
+		Corporations: make([]dashboardCorporation, 0, len(corporations)),
+		RecentPosts:  server.parser.RecentPosts(),
+		Status:       server.parser.Status(),
+	}
+
+	for _, corporation := range corporations {
+		killmails, err := server.database.LoadKillmails(corporation.ID, from, to)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		page.Corporations = append(page.Corporations, dashboardCorporation{
+			Corporation: corporation,
+			Name:        parser.CorporationName(corporation),
+			Digest:      reports.GenerateDigest(corporation, killmails, from, to, 0, server.parser.Prices()),
+		})
+	}
+
+	server.render(w, "index", page)
+}
+
+// handleDashboardCorporation renders the recent activity and statistics of a single corporation
+func (server *Server) handleDashboardCorporation(w http.ResponseWriter, r *http.Request) {
+	value := strings.Trim(strings.TrimPrefix(r.URL.Path, "/corporation/"), "/")
+
+	eveCorporationID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	corporation, err := server.database.LoadCorporationByEVECorporationID(eveCorporationID)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	to := time.Now().UTC()
+	from := reports.WeekStart(to).AddDate(0, 0, -7*(dashboardWeeks-1))
+
+	killmails, err := server.database.LoadKillmails(corporation.ID, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	prices := server.parser.Prices()
+
+	page := &corporationPage{
+		Title:     parser.CorporationName(corporation),
+		Generated: to,
+		Weeks:     scaleWeeks(reports.GenerateWeeklyTotals(killmails, to, dashboardWeeks, prices)),
+		TopPilots: make([]dashboardPilots, 0),
+		Recent:    make([]dashboardKillmail, 0),
+	}
+
+	monthStart := to.AddDate(0, 0, -30)
+	recent := make([]*models.Killmail, 0, len(killmails))
+	for _, killmail := range killmails {
+		if !killmail.KillTime.Before(monthStart) {
+			recent = append(recent, killmail)
+		}
+	}
+
+	leaderboard := reports.GenerateLeaderboard(corporation, recent, monthStart, to, dashboardRankingSize, prices)
+	server.parser.FillPilotNames(leaderboard.KillsParticipated)
+	server.parser.FillPilotNames(leaderboard.ISKDestroyed)
+
+	for i := 0; i < len(leaderboard.KillsParticipated) || i < len(leaderboard.ISKDestroyed); i++ {
+		var row dashboardPilots
+
+		if i < len(leaderboard.KillsParticipated) {
+			row.Kills = &leaderboard.KillsParticipated[i]
+		}
+		if i < len(leaderboard.ISKDestroyed) {
+			row.ISK = &leaderboard.ISKDestroyed[i]
+		}
+
+		page.TopPilots = append(page.TopPilots, row)
+	}
+
+	for i := len(killmails) - 1; i >= 0 && len(page.Recent) < dashboardRecentSize; i-- {
+		killmail := killmails[i]
+		server.parser.FillNames(&killmail.Entry)
+
+		victim := killmail.Entry.Victim.CharacterName
+		if len(victim) == 0 {
+			victim = killmail.Entry.Victim.CorporationName
+		}
+
+		page.Recent = append(page.Recent, dashboardKillmail{
+			KillID:   killmail.KillID,
+			Type:     killmail.Type.String(),
+			KillTime: killmail.KillTime,
+			Victim:   victim,
+			ShipName: server.parser.ItemTypeName(killmail.Entry.Victim.ShipTypeID),
+			Value:    killmail.Entry.Value(prices),
+		})
+	}
+
+	server.render(w, "corporation", page)
+}
+
+// render executes the given dashboard template, logging errors occurring while writing the response
+func (server *Server) render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	err := server.templates.ExecuteTemplate(w, name, data)
+	if err != nil {
+		misc.Logger.Warnf("Failed to render dashboard template %q: [%v]", name, err)
+	}
+}
+
+// scaleWeeks calculates the bar widths of the given weekly totals, scaled to the largest value
+func scaleWeeks(totals []reports.WeeklyTotal) []dashboardWeek {
+	var max float64
+
+	for _, total := range totals {
+		if total.ISKDestroyed > max {
+			max = total.ISKDestroyed
+		}
+		if total.ISKLost > max {
+			max = total.ISKLost
+		}
+	}
+
+	weeks := make([]dashboardWeek, 0, len(totals))
+
+	for _, total := range totals {
+		week := dashboardWeek{
+			WeeklyTotal: total,
+		}
+
+		if max > 0 {
+			week.DestroyedWidth = total.ISKDestroyed / max * dashboardBarWidth
+			week.LostWidth = total.ISKLost / max * dashboardBarWidth
+		}
+
+		weeks = append(weeks, week)
+	}
+
+	return weeks
+}
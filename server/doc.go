@@ -0,0 +1,3 @@
+// Package server provides an embedded HTTP server exposing an administrative JSON API and a web dashboard, allowing the tracked corporations to be managed and monitored while the application is running.
+// All requests except Slack callbacks, which are verified using the Slack signing secret, and health checks have to be authenticated using the admin token set in the configuration.
+package server
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/database"
+	"github.com/morpheusxaut/eveslackkills/parser"
+)
+
+// healthCheck represents the result of checking a single dependency of the application
+type healthCheck struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+}
+
+// corporationHealth represents the time since the last successful update of a single corporation
+type corporationHealth struct {
+	CorporationID int64     `json:"corporationID"`
+	LastUpdate    time.Time `json:"lastUpdate"`
+	Age           string    `json:"age"`
+	Stale         bool      `json:"stale"`
+}
+
+// healthReport represents the response of the health and readiness endpoints
+type healthReport struct {
+	Status                string              `json:"status"`
+	Checks                []healthCheck       `json:"checks"`
+	LastHeartbeat         time.Time           `json:"lastHeartbeat"`
+	Corporations          []corporationHealth `json:"corporations"`
+	LastZKillboardError   string              `json:"lastZKillboardError,omitempty"`
+	LastZKillboardErrorAt *time.Time          `json:"lastZKillboardErrorAt,omitempty"`
+	LastSlackError        string              `json:"lastSlackError,omitempty"`
+	LastSlackErrorAt      *time.Time          `json:"lastSlackErrorAt,omitempty"`
+}
+
+// handleHealth reports whether the application is alive, failing if the update loop has not made progress for too long
+func (server *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	status := server.parser.Status()
+	maxAge := server.maxUpdateAge()
+	now := time.Now()
+
+	report := newHealthReport(status, maxAge, now)
+
+	report.Checks = append(report.Checks, checkHeartbeat(status, maxAge, now))
+
+	writeHealthReport(w, report)
+}
+
+// handleReady reports whether the application is ready to work, failing if the database or lookup service is unreachable or the first update cycle has not finished yet
+func (server *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	status := server.parser.Status()
+
+	report := newHealthReport(status, server.maxUpdateAge(), time.Now())
+
+	report.Checks = append(report.Checks, checkDatabase(server.database))
+	report.Checks = append(report.Checks, checkLookup(status))
+	report.Checks = append(report.Checks, checkFirstUpdate(status))
+
+	writeHealthReport(w, report)
+}
+
+// maxUpdateAge returns the time without progress of the update loop after which the application is considered unhealthy, also used for flagging stale corporations
+func (server *Server) maxUpdateAge() time.Duration {
+	if server.config.HealthMaxUpdateAge > 0 {
+		return time.Minute * time.Duration(server.config.HealthMaxUpdateAge)
+	}
+
+	return server.parser.UpdateInterval() * 3
+}
+
+// newHealthReport creates a report containing the last heartbeat of the update loop, the time since the last successful update of every corporation and the last zKillboard and Slack errors.
+// Corporations without a successful update within the given maximum age are flagged as stale
+func newHealthReport(status parser.Status, maxAge time.Duration, now time.Time) *healthReport {
+	report := &healthReport{
+		Checks:        make([]healthCheck, 0),
+		LastHeartbeat: status.LastHeartbeat,
+		Corporations:  make([]corporationHealth, 0, len(status.CorporationUpdates)),
+	}
+
+	for corporationID, lastUpdate := range status.CorporationUpdates {
+		since := lastUpdate
+		if since.IsZero() {
+			since = status.StartedAt
+		}
+
+		age := now.Sub(since)
+
+		report.Corporations = append(report.Corporations, corporationHealth{
+			CorporationID: corporationID,
+			LastUpdate:    lastUpdate,
+			Age:           age.Truncate(time.Second).String(),
+			Stale:         age > maxAge,
+		})
+	}
+
+	sort.Sort(byCorporationID(report.Corporations))
+
+	if len(status.LastZKillboardError) > 0 {
+		report.LastZKillboardError = status.LastZKillboardError
+		report.LastZKillboardErrorAt = &status.LastZKillboardErrorAt
+	}
+
+	if len(status.LastSlackError) > 0 {
+		report.LastSlackError = status.LastSlackError
+		report.LastSlackErrorAt = &status.LastSlackErrorAt
+	}
+
+	return report
+}
+
+// checkDatabase verifies the given database backend is reachable
+func checkDatabase(db database.Connection) healthCheck {
+	err := db.Ping()
+	if err != nil {
+		return healthCheck{Name: "database", OK: false, Message: err.Error()}
+	}
+
+	return healthCheck{Name: "database", OK: true}
+}
+
+// checkLookup verifies the lookup service used for static universe data was reachable during the most recent update
+func checkLookup(status parser.Status) healthCheck {
+	if status.LookupCheckedAt.IsZero() {
+		return healthCheck{Name: "lookup", OK: false, Message: "Lookup service has not been checked yet"}
+	}
+
+	if len(status.LookupError) > 0 {
+		return healthCheck{Name: "lookup", OK: false, Message: status.LookupError}
+	}
+
+	return healthCheck{Name: "lookup", OK: true}
+}
+
+// checkFirstUpdate verifies the parser finished its first update cycle
+func checkFirstUpdate(status parser.Status) healthCheck {
+	if status.LastUpdate.IsZero() {
+		return healthCheck{Name: "updates", OK: false, Message: "First update cycle has not finished yet"}
+	}
+
+	return healthCheck{Name: "updates", OK: true}
+}
+
+// checkHeartbeat verifies the update loop has made progress within the given maximum age
+func checkHeartbeat(status parser.Status, maxAge time.Duration, now time.Time) healthCheck {
+	age := now.Sub(status.LastHeartbeat)
+	if age > maxAge {
+		return healthCheck{Name: "heartbeat", OK: false, Message: fmt.Sprintf("Update loop has not made progress for %s", age.Truncate(time.Second))}
+	}
+
+	return healthCheck{Name: "heartbeat", OK: true}
+}
+
+// writeHealthReport sends the given report as JSON response, using status code 503 if any check failed
+func writeHealthReport(w http.ResponseWriter, report *healthReport) {
+	report.Status = "ok"
+	status := http.StatusOK
+
+	for _, check := range report.Checks {
+		if !check.OK {
+			report.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	writeJSON(w, status, report)
+}
+
+// byCorporationID represents an array of corporation health reports, used for sorting by corporation ID
+type byCorporationID []corporationHealth
+
+// Len returns the length of the array of corporation health reports to sort
+func (c byCorporationID) Len() int {
+	return len(c)
+}
+
+// Swap swaps two entries in the array of corporation health reports to sort
+func (c byCorporationID) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+
+// Less is used for sorting the array of corporation health reports by ascending corporation ID
+func (c byCorporationID) Less(i, j int) bool {
+	return c[i].CorporationID < c[j].CorporationID
+}
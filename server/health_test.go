@@ -0,0 +1,226 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/database"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/parser"
+)
+
+// fakeDatabase implements the database connection used by the health checks, answering pings with the configured error
+type fakeDatabase struct {
+	database.Connection
+	err error
+}
+
+// Ping returns the configured error
+func (db *fakeDatabase) Ping() error {
+	return db.err
+}
+
+func TestCheckHeartbeat(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		lastHeartbeat time.Time
+		ok            bool
+	}{
+		{
+			name:          "recent heartbeat",
+			lastHeartbeat: now.Add(-time.Minute),
+			ok:            true,
+		},
+		{
+			name:          "heartbeat at maximum age",
+			lastHeartbeat: now.Add(-time.Minute * 15),
+			ok:            true,
+		},
+		{
+			name:          "stale heartbeat",
+			lastHeartbeat: now.Add(-time.Minute * 16),
+			ok:            false,
+		},
+	}
+
+	for _, test := range tests {
+		check := checkHeartbeat(parser.Status{LastHeartbeat: test.lastHeartbeat}, time.Minute*15, now)
+		if check.OK != test.ok {
+			t.Errorf("%s: expected ok %v, got %v (%s)", test.name, test.ok, check.OK, check.Message)
+		}
+		if check.Name != "heartbeat" {
+			t.Errorf("%s: expected check name %q, got %q", test.name, "heartbeat", check.Name)
+		}
+	}
+}
+
+func TestCheckLookup(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  parser.Status
+		ok      bool
+		message string
+	}{
+		{
+			name:    "not checked yet",
+			status:  parser.Status{},
+			ok:      false,
+			message: "Lookup service has not been checked yet",
+		},
+		{
+			name:    "last check failed",
+			status:  parser.Status{LookupCheckedAt: time.Now(), LookupError: "ESI unreachable"},
+			ok:      false,
+			message: "ESI unreachable",
+		},
+		{
+			name:   "last check succeeded",
+			status: parser.Status{LookupCheckedAt: time.Now()},
+			ok:     true,
+		},
+	}
+
+	for _, test := range tests {
+		check := checkLookup(test.status)
+		if check.OK != test.ok {
+			t.Errorf("%s: expected ok %v, got %v", test.name, test.ok, check.OK)
+		}
+		if check.Message != test.message {
+			t.Errorf("%s: expected message %q, got %q", test.name, test.message, check.Message)
+		}
+	}
+}
+
+func TestCheckFirstUpdate(t *testing.T) {
+	if check := checkFirstUpdate(parser.Status{}); check.OK {
+		t.Errorf("expected check to fail before the first update cycle finished")
+	}
+
+	if check := checkFirstUpdate(parser.Status{LastUpdate: time.Now()}); !check.OK {
+		t.Errorf("expected check to pass after the first update cycle finished, got %q", check.Message)
+	}
+}
+
+func TestCheckDatabase(t *testing.T) {
+	if check := checkDatabase(&fakeDatabase{}); !check.OK {
+		t.Errorf("expected check to pass for reachable database, got %q", check.Message)
+	}
+
+	check := checkDatabase(&fakeDatabase{err: fmt.Errorf("connection refused")})
+	if check.OK {
+		t.Errorf("expected check to fail for unreachable database")
+	}
+	if check.Message != "connection refused" {
+		t.Errorf("expected message %q, got %q", "connection refused", check.Message)
+	}
+}
+
+func TestNewHealthReport(t *testing.T) {
+	now := time.Now()
+
+	status := parser.Status{
+		StartedAt:     now.Add(-time.Hour),
+		LastHeartbeat: now.Add(-time.Minute),
+		CorporationUpdates: map[int64]time.Time{
+			98388312: now.Add(-time.Minute * 5),
+			1000001:  now.Add(-time.Minute * 20),
+			1000002:  {},
+		},
+		LastSlackError:   "rate limited",
+		LastSlackErrorAt: now.Add(-time.Minute * 2),
+	}
+
+	report := newHealthReport(status, time.Minute*15, now)
+
+	expected := []struct {
+		corporationID int64
+		stale         bool
+	}{
+		{corporationID: 1000001, stale: true},
+		{corporationID: 1000002, stale: true},
+		{corporationID: 98388312, stale: false},
+	}
+
+	if len(report.Corporations) != len(expected) {
+		t.Fatalf("expected %d corporations, got %d", len(expected), len(report.Corporations))
+	}
+
+	for i, corporation := range expected {
+		if report.Corporations[i].CorporationID != corporation.corporationID {
+			t.Errorf("corporation %d: expected ID %d, got %d", i, corporation.corporationID, report.Corporations[i].CorporationID)
+		}
+		if report.Corporations[i].Stale != corporation.stale {
+			t.Errorf("corporation #%d: expected stale %v, got %v", corporation.corporationID, corporation.stale, report.Corporations[i].Stale)
+		}
+	}
+
+	if report.Corporations[1].Age != "1h0m0s" {
+		t.Errorf("expected corporation without update to age since start, got %q", report.Corporations[1].Age)
+	}
+
+	if report.LastSlackError != "rate limited" || report.LastSlackErrorAt == nil {
+		t.Errorf("expected last Slack error to be reported, got %q", report.LastSlackError)
+	}
+	if report.LastZKillboardErrorAt != nil {
+		t.Errorf("expected no zKillboard error to be reported, got %v", report.LastZKillboardErrorAt)
+	}
+}
+
+func TestMaxUpdateAge(t *testing.T) {
+	server := &Server{
+		config: &misc.Configuration{HealthMaxUpdateAge: 20},
+	}
+
+	if maxAge := server.maxUpdateAge(); maxAge != time.Minute*20 {
+		t.Errorf("expected configured maximum age of 20m0s, got %s", maxAge)
+	}
+}
+
+func TestWriteHealthReport(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []healthCheck
+		code   int
+		status string
+	}{
+		{
+			name:   "all checks passed",
+			checks: []healthCheck{{Name: "database", OK: true}, {Name: "lookup", OK: true}},
+			code:   http.StatusOK,
+			status: "ok",
+		},
+		{
+			name:   "single check failed",
+			checks: []healthCheck{{Name: "database", OK: true}, {Name: "lookup", OK: false, Message: "ESI unreachable"}},
+			code:   http.StatusServiceUnavailable,
+			status: "unavailable",
+		},
+	}
+
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+
+		writeHealthReport(recorder, &healthReport{Checks: test.checks})
+
+		if recorder.Code != test.code {
+			t.Errorf("%s: expected status code %d, got %d", test.name, test.code, recorder.Code)
+		}
+
+		var report healthReport
+		err := json.Unmarshal(recorder.Body.Bytes(), &report)
+		if err != nil {
+			t.Errorf("%s: failed to decode report: %v", test.name, err)
+			continue
+		}
+
+		if report.Status != test.status {
+			t.Errorf("%s: expected status %q, got %q", test.name, test.status, report.Status)
+		}
+	}
+}
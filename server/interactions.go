@@ -0,0 +1,178 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+	"github.com/morpheusxaut/eveslackkills/parser"
+)
+
+// interactionHandler handles a single button attached to kill and loss messages, returning the payload to respond with or an error if the action failed
+type interactionHandler func(server *Server, interaction *models.SlackInteraction, corporation *models.Corporation, killmail *models.Killmail) (*models.SlackPayload, error)
+
+var (
+	interactionHandlers map[string]interactionHandler
+)
+
+func init() {
+	interactionHandlers = map[string]interactionHandler{
+		parser.ActionIgnoreSystem:  runInteractionIgnoreSystem,
+		parser.ActionShowFitting:   runInteractionShowFitting,
+		parser.ActionShowAttackers: runInteractionShowAttackers,
+		parser.ActionMarkReviewed:  runInteractionMarkReviewed,
+	}
+}
+
+// handleInteraction verifies and answers a button clicked on a kill or loss message, responding via the response URL if answering takes too long
+func (server *Server) handleInteraction(w http.ResponseWriter, r *http.Request) {
+	form, err := server.verifySlackRequest(w, r)
+	if err != nil {
+		misc.Logger.Warnf("Rejected Slack interaction from %s: [%v]", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var interaction *models.SlackInteraction
+
+	err = json.Unmarshal([]byte(form.Get("payload")), &interaction)
+	if err != nil || interaction == nil {
+		misc.Logger.Warnf("Failed to parse Slack interaction payload: [%v]", err)
+		http.Error(w, "Invalid interaction payload", http.StatusBadRequest)
+		return
+	}
+
+	if len(interaction.Actions) == 0 {
+		http.Error(w, "Missing interaction action", http.StatusBadRequest)
+		return
+	}
+
+	misc.Logger.Debugf("Handling Slack interaction %q for %q from user %q", interaction.Actions[0].Name, interaction.CallbackID, interaction.User.Name)
+
+	done := make(chan *models.SlackPayload, 1)
+	go func() {
+		done <- server.runInteraction(interaction)
+	}()
+
+	select {
+	case payload := <-done:
+		writeJSON(w, http.StatusOK, payload)
+	case <-time.After(slackResponseTimeout):
+		writeJSON(w, http.StatusOK, slashText("Working on it..."))
+
+		go func() {
+			err := server.parser.SendResponse(interaction.ResponseURL, <-done)
+			if err != nil {
+				misc.Logger.Warnf("Failed to send delayed response to Slack interaction: [%v]", err)
+			}
+		}()
+	}
+}
+
+// runInteraction loads the kill or loss referenced by the interaction and executes the clicked action, returning a payload containing the result or the encountered error
+func (server *Server) runInteraction(interaction *models.SlackInteraction) *models.SlackPayload {
+	action := interaction.Actions[0].Name
+
+	handler, ok := interactionHandlers[action]
+	if !ok {
+		return slashText(fmt.Sprintf("Unknown action %q", action))
+	}
+
+	eveCorporationID, killID, entryType, err := parser.ParseKillmailCallbackID(interaction.CallbackID)
+	if err != nil {
+		return slashText(fmt.Sprintf("Error: %v", err))
+	}
+
+	corporation, err := server.database.LoadCorporationByEVECorporationID(eveCorporationID)
+	if err != nil {
+		misc.Logger.Warnf("Failed to load corporation #%d for Slack interaction: [%v]", eveCorporationID, err)
+		return slashText(fmt.Sprintf("Corporation #%d is not tracked anymore", eveCorporationID))
+	}
+
+	killmail, err := server.database.LoadKillmail(corporation.ID, killID, entryType)
+	if err != nil {
+		misc.Logger.Warnf("Failed to load %s #%d for Slack interaction: [%v]", entryType, killID, err)
+		return slashText(fmt.Sprintf("Failed to find %s #%d", entryType, killID))
+	}
+
+	payload, err := handler(server, interaction, corporation, killmail)
+	if err != nil {
+		return slashText(fmt.Sprintf("Error: %v", err))
+	}
+
+	return payload
+}
+
+// runInteractionIgnoreSystem adds the solar system of the kill or loss to the ignore list of the corporation if the user or channel is allowed to do so
+func runInteractionIgnoreSystem(server *Server, interaction *models.SlackInteraction, corporation *models.Corporation, killmail *models.Killmail) (*models.SlackPayload, error) {
+	if !server.slackAllowed(interaction.User.ID, interaction.Channel.ID) {
+		return slashText("You are not allowed to ignore solar systems"), nil
+	}
+
+	err := server.database.AddIgnoredSolarSystem(corporation.ID, killmail.SolarSystemID)
+	if err != nil {
+		return nil, err
+	}
+
+	misc.Logger.Infof("Ignoring solar system #%d for corporation #%d via Slack interaction of user %q", killmail.SolarSystemID, corporation.EVECorporationID, interaction.User.Name)
+
+	server.parser.TriggerUpdate()
+
+	return slashText(fmt.Sprintf("Kills and losses of %s in %s will be ignored from now on", parser.CorporationName(corporation), server.parser.SolarSystemName(killmail.SolarSystemID))), nil
+}
+
+// runInteractionShowFitting lists all modules fitted to the ship of the kill or loss
+func runInteractionShowFitting(server *Server, interaction *models.SlackInteraction, corporation *models.Corporation, killmail *models.Killmail) (*models.SlackPayload, error) {
+	fitting := server.parser.FittingDetails(killmail.Entry)
+	if len(fitting) == 0 {
+		return slashText(fmt.Sprintf("No fitted modules found for %s #%d", killmail.Type, killmail.KillID)), nil
+	}
+
+	payload := slashText(fmt.Sprintf("Fitting of <https://zkillboard.com/kill/%d/|%s #%d>", killmail.KillID, killmail.Type, killmail.KillID))
+	payload.Attachments = append(payload.Attachments, models.SlackAttachment{
+		Fallback: fitting,
+		Text:     fitting,
+	})
+
+	return payload, nil
+}
+
+// runInteractionShowAttackers lists the attackers involved in the kill or loss
+func runInteractionShowAttackers(server *Server, interaction *models.SlackInteraction, corporation *models.Corporation, killmail *models.Killmail) (*models.SlackPayload, error) {
+	attackers := server.parser.AttackerDetails(killmail.Entry)
+	if len(attackers) == 0 {
+		return slashText(fmt.Sprintf("No attackers found for %s #%d", killmail.Type, killmail.KillID)), nil
+	}
+
+	payload := slashText(fmt.Sprintf("%d attackers involved in <https://zkillboard.com/kill/%d/|%s #%d>", len(killmail.Entry.Attackers), killmail.KillID, killmail.Type, killmail.KillID))
+	payload.Attachments = append(payload.Attachments, models.SlackAttachment{
+		Fallback: attackers,
+		Text:     attackers,
+	})
+
+	return payload, nil
+}
+
+// runInteractionMarkReviewed flags the kill or loss as reviewed by the user clicking the button
+func runInteractionMarkReviewed(server *Server, interaction *models.SlackInteraction, corporation *models.Corporation, killmail *models.Killmail) (*models.SlackPayload, error) {
+	if killmail.Reviewed {
+		return slashText(fmt.Sprintf("The %s #%d has already been reviewed by %s", killmail.Type, killmail.KillID, killmail.ReviewedBy)), nil
+	}
+
+	err := server.database.MarkKillmailReviewed(killmail.ID, interaction.User.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	misc.Logger.Infof("Marked %s #%d of corporation #%d as reviewed by user %q", killmail.Type, killmail.KillID, corporation.EVECorporationID, interaction.User.Name)
+
+	payload := &models.SlackPayload{
+		ResponseType: "in_channel",
+		Text:         fmt.Sprintf("The <https://zkillboard.com/kill/%d/|%s #%d> has been reviewed by %s", killmail.KillID, killmail.Type, killmail.KillID, interaction.User.Name),
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/database"
+	"github.com/morpheusxaut/eveslackkills/metrics"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/parser"
+)
+
+// Server represents the embedded HTTP server used for administrating the application
+type Server struct {
+	config    *misc.Configuration
+	database  database.Connection
+	parser    *parser.Parser
+	mux       *http.ServeMux
+	templates *template.Template
+}
+
+// SetupServer sets up a new server with the given information, returning an error if no admin token was configured
+func SetupServer(conf *misc.Configuration, db database.Connection, parse *parser.Parser) (*Server, error) {
+	if len(conf.AdminToken) == 0 {
+		return nil, fmt.Errorf("No admin token configured")
+	}
+
+	server := &Server{
+		config:    conf,
+		database:  db,
+		parser:    parse,
+		mux:       http.NewServeMux(),
+		templates: parseDashboardTemplates(),
+	}
+
+	server.mux.HandleFunc("/api/corporations", server.authenticated(server.handleCorporations))
+	server.mux.HandleFunc("/api/corporations/", server.authenticated(server.handleCorporation))
+	server.mux.HandleFunc("/api/update", server.authenticated(server.handleUpdate))
+	server.mux.HandleFunc("/api/retries", server.authenticated(server.handleRetries))
+	server.mux.HandleFunc("/api/posts", server.authenticated(server.handlePosts))
+	server.mux.HandleFunc("/metrics", server.authenticated(metrics.Default.ServeHTTP))
+	server.mux.HandleFunc("/healthz", server.handleHealth)
+	server.mux.HandleFunc("/readyz", server.handleReady)
+	if len(conf.SlackSigningSecret) > 0 {
+		server.mux.HandleFunc("/slack/commands", server.handleSlashCommand)
+		server.mux.HandleFunc("/slack/interactions", server.handleInteraction)
+	}
+
+	server.mux.HandleFunc("/", server.authenticated(server.handleDashboard))
+	server.mux.HandleFunc("/corporation/", server.authenticated(server.handleDashboardCorporation))
+
+	return server, nil
+}
+
+// ListenAndServe starts listening on the address set in the configuration, blocking until the server fails
+func (server *Server) ListenAndServe() error {
+	httpServer := &http.Server{
+		Addr:         server.config.AdminListenAddress,
+		Handler:      server.mux,
+		ReadTimeout:  time.Second * 30,
+		WriteTimeout: time.Second * 30,
+	}
+
+	misc.Logger.Infof("Starting admin server on %q", server.config.AdminListenAddress)
+
+	return httpServer.ListenAndServe()
+}
+
+// authenticated wraps the given handler, rejecting all requests not providing the configured admin token, either as bearer token or as password for basic authentication (allowing browsers to access the dashboard)
+func (server *Server) authenticated(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if _, password, ok := r.BasicAuth(); ok {
+			token = password
+		}
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(server.config.AdminToken)) != 1 {
+			misc.Logger.Warnf("Rejected unauthenticated admin request for %q from %s", r.URL.Path, r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", `Basic realm="eveslackkills"`)
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("Invalid or missing admin token"))
+			return
+		}
+
+		misc.Logger.Tracef("Handling admin request %s %q", r.Method, r.URL.Path)
+
+		handler(w, r)
+	}
+}
+
+// handleUpdate triggers an immediate reload of all corporations followed by an update
+func (server *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s not allowed", r.Method))
+		return
+	}
+
+	queued := server.parser.TriggerUpdate()
+
+	writeJSON(w, http.StatusAccepted, map[string]bool{"queued": queued})
+}
+
+// handleRetries lists all messages waiting to be retried
+func (server *Server) handleRetries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s not allowed", r.Method))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, server.parser.RetryQueue())
+}
+
+// handlePosts lists the most recently posted messages
+func (server *Server) handlePosts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s not allowed", r.Method))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, server.parser.RecentPosts())
+}
+
+// writeJSON encodes the given value as JSON response using the provided status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		misc.Logger.Warnf("Failed to encode admin response: [%v]", err)
+	}
+}
+
+// writeError sends the given error as JSON response using the provided status code
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
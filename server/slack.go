@@ -0,0 +1,58 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// slackRequestMaxAge represents the maximum age of a signed Slack request, protecting against replay attacks
+	slackRequestMaxAge = time.Minute * 5
+	// slackResponseTimeout represents the time available for answering a Slack request directly before responding via the response URL instead
+	slackResponseTimeout = time.Millisecond * 2500
+	// maxSlackRequestSize represents the maximum size of a Slack request body
+	maxSlackRequestSize = 1 << 20
+)
+
+// verifySlackRequest reads the body of the given request and verifies its signature using the configured signing secret, returning the parsed form values or an error if the request is invalid
+func (server *Server) verifySlackRequest(w http.ResponseWriter, r *http.Request) (url.Values, error) {
+	if r.Method != "POST" {
+		return nil, fmt.Errorf("Method %s not allowed", r.Method)
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackRequestSize))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := r.Header.Get("X-Slack-Request-Timestamp")
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid request timestamp %q", timestamp)
+	}
+
+	age := time.Since(time.Unix(seconds, 0))
+	if age > slackRequestMaxAge || age < -slackRequestMaxAge {
+		return nil, fmt.Errorf("Request timestamp %q is too old", timestamp)
+	}
+
+	mac := hmac.New(sha256.New, []byte(server.config.SlackSigningSecret))
+	mac.Write([]byte(fmt.Sprintf("v0:%s:", timestamp)))
+	mac.Write(body)
+
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Slack-Signature"))) {
+		return nil, fmt.Errorf("Invalid request signature")
+	}
+
+	return url.ParseQuery(string(body))
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+)
+
+const (
+	testSigningSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+	testSlackBody     = "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&command=%2Fkills&text=stats+week"
+)
+
+// signSlackBody calculates the Slack signature of the given body and timestamp using the provided secret
+func signSlackBody(secret string, timestamp string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("v0:%s:%s", timestamp, body)))
+
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySlackRequest(t *testing.T) {
+	server := &Server{
+		config: &misc.Configuration{SlackSigningSecret: testSigningSecret},
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Minute*10).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		method    string
+		body      string
+		timestamp string
+		signature string
+		valid     bool
+	}{
+		{
+			name:      "valid signature",
+			method:    "POST",
+			body:      testSlackBody,
+			timestamp: now,
+			signature: signSlackBody(testSigningSecret, now, testSlackBody),
+			valid:     true,
+		},
+		{
+			name:      "tampered body",
+			method:    "POST",
+			body:      strings.Replace(testSlackBody, "stats", "ignore", 1),
+			timestamp: now,
+			signature: signSlackBody(testSigningSecret, now, testSlackBody),
+			valid:     false,
+		},
+		{
+			name:      "wrong secret",
+			method:    "POST",
+			body:      testSlackBody,
+			timestamp: now,
+			signature: signSlackBody("another secret", now, testSlackBody),
+			valid:     false,
+		},
+		{
+			name:      "stale timestamp",
+			method:    "POST",
+			body:      testSlackBody,
+			timestamp: stale,
+			signature: signSlackBody(testSigningSecret, stale, testSlackBody),
+			valid:     false,
+		},
+		{
+			name:      "missing signature header",
+			method:    "POST",
+			body:      testSlackBody,
+			timestamp: now,
+			signature: "",
+			valid:     false,
+		},
+		{
+			name:      "missing timestamp header",
+			method:    "POST",
+			body:      testSlackBody,
+			timestamp: "",
+			signature: signSlackBody(testSigningSecret, now, testSlackBody),
+			valid:     false,
+		},
+		{
+			name:      "wrong method",
+			method:    "GET",
+			body:      testSlackBody,
+			timestamp: now,
+			signature: signSlackBody(testSigningSecret, now, testSlackBody),
+			valid:     false,
+		},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(test.method, "/slack/commands", strings.NewReader(test.body))
+		if len(test.timestamp) > 0 {
+			r.Header.Set("X-Slack-Request-Timestamp", test.timestamp)
+		}
+		if len(test.signature) > 0 {
+			r.Header.Set("X-Slack-Signature", test.signature)
+		}
+
+		form, err := server.verifySlackRequest(httptest.NewRecorder(), r)
+		if test.valid && err != nil {
+			t.Errorf("%s: expected request to be accepted, got error %v", test.name, err)
+			continue
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected request to be rejected", test.name)
+			continue
+		}
+
+		if test.valid && form.Get("text") != "stats week" {
+			t.Errorf("%s: expected text %q, got %q", test.name, "stats week", form.Get("text"))
+		}
+	}
+}
+
+func TestVerifySlackRequestRejectsOversizedBody(t *testing.T) {
+	server := &Server{
+		config: &misc.Configuration{SlackSigningSecret: testSigningSecret},
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	body := strings.Repeat("a", maxSlackRequestSize+1)
+
+	r := httptest.NewRequest("POST", "/slack/commands", strings.NewReader(body))
+	r.Header.Set("X-Slack-Request-Timestamp", now)
+	r.Header.Set("X-Slack-Signature", signSlackBody(testSigningSecret, now, body))
+
+	_, err := server.verifySlackRequest(httptest.NewRecorder(), r)
+	if err == nil {
+		t.Errorf("Expected oversized request to be rejected")
+	}
+}
+
+func TestParseSlashPeriod(t *testing.T) {
+	tests := []struct {
+		args      []string
+		days      int
+		remaining int
+	}{
+		{args: []string{}, days: slashDefaultDays, remaining: 0},
+		{args: []string{"day"}, days: 1, remaining: 0},
+		{args: []string{"corp", "week"}, days: 7, remaining: 1},
+		{args: []string{"month"}, days: 30, remaining: 0},
+		{args: []string{"14d"}, days: 14, remaining: 0},
+		{args: []string{"100000d"}, days: slashMaxDays, remaining: 0},
+		{args: []string{"0d"}, days: slashDefaultDays, remaining: 1},
+		{args: []string{"New", "Caldari"}, days: slashDefaultDays, remaining: 2},
+	}
+
+	for _, test := range tests {
+		days, remaining := parseSlashPeriod(test.args)
+		if days != test.days || len(remaining) != test.remaining {
+			t.Errorf("parseSlashPeriod(%q): expected %d days and %d remaining arguments, got %d and %d", test.args, test.days, test.remaining, days, len(remaining))
+		}
+	}
+}
+
+func TestSlackAllowed(t *testing.T) {
+	server := &Server{
+		config: &misc.Configuration{SlackIgnoreAllowList: []string{"U123", "C789", " "}},
+	}
+
+	tests := []struct {
+		userID    string
+		channelID string
+		allowed   bool
+	}{
+		{userID: "U123", channelID: "C1", allowed: true},
+		{userID: "U456", channelID: "C789", allowed: true},
+		{userID: "U456", channelID: "C1", allowed: false},
+		{userID: "", channelID: "", allowed: false},
+	}
+
+	for _, test := range tests {
+		allowed := server.slackAllowed(test.userID, test.channelID)
+		if allowed != test.allowed {
+			t.Errorf("slackAllowed(%q, %q): expected %v, got %v", test.userID, test.channelID, test.allowed, allowed)
+		}
+	}
+
+	server.config.SlackIgnoreAllowList = nil
+
+	if server.slackAllowed("U123", "C789") {
+		t.Errorf("Expected nobody to be allowed without allow list")
+	}
+}
@@ -0,0 +1,590 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+	"github.com/morpheusxaut/eveslackkills/parser"
+	"github.com/morpheusxaut/eveslackkills/reports"
+)
+
+const (
+	// slashDefaultDays represents the period in days used by slash commands if none was given
+	slashDefaultDays = 7
+	// slashHistoryDays represents the period in days searched by slash commands not accepting a period
+	slashHistoryDays = 30
+	// slashRankingSize represents the number of entries listed by slash commands
+	slashRankingSize = 5
+	// slashMaxDays represents the longest period in days accepted by slash commands
+	slashMaxDays = 365
+)
+
+// slashCommand represents a single action of the Slack slash command
+type slashCommand struct {
+	// Name represents the name used to invoke the action
+	Name string
+	// Usage represents the arguments accepted by the action
+	Usage string
+	// Description represents a short explanation of the action
+	Description string
+	// Restricted represents whether the action may only be used by users or in channels on the Slack allow list
+	Restricted bool
+	// Run executes the action with the given arguments, returning the payload to respond with or an error if the execution failed
+	Run func(server *Server, args []string) (*models.SlackPayload, error)
+}
+
+var (
+	slashCommands []*slashCommand
+)
+
+func init() {
+	slashCommands = []*slashCommand{
+		{Name: "stats", Usage: "[corporation] [day|week|month|<days>d]", Description: "Shows kill and loss statistics", Run: runSlashStats},
+		{Name: "top", Usage: "[corporation] [day|week|month|<days>d]", Description: "Shows the top pilots", Run: runSlashTop},
+		{Name: "pilot", Usage: "<name>", Description: "Shows the statistics of a pilot for the last 30 days", Run: runSlashPilot},
+		{Name: "ignore", Usage: "<solar system> [corporation]", Description: "Ignores kills and losses in a solar system", Restricted: true, Run: runSlashIgnore},
+		{Name: "last", Usage: "[corporation]", Description: "Lists the latest kills and losses", Run: runSlashLast},
+		{Name: "help", Description: "Lists all available commands", Run: runSlashHelp},
+	}
+}
+
+// handleSlashCommand verifies and answers a Slack slash command, responding via the response URL if answering takes too long
+func (server *Server) handleSlashCommand(w http.ResponseWriter, r *http.Request) {
+	form, err := server.verifySlackRequest(w, r)
+	if err != nil {
+		misc.Logger.Warnf("Rejected Slack slash command from %s: [%v]", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	args := strings.Fields(form.Get("text"))
+	responseURL := form.Get("response_url")
+
+	misc.Logger.Debugf("Handling Slack slash command %q from user %q", form.Get("text"), form.Get("user_name"))
+
+	allowed := server.slackAllowed(form.Get("user_id"), form.Get("channel_id"))
+
+	done := make(chan *models.SlackPayload, 1)
+	go func() {
+		done <- server.runSlashCommand(args, allowed)
+	}()
+
+	select {
+	case payload := <-done:
+		writeJSON(w, http.StatusOK, payload)
+	case <-time.After(slackResponseTimeout):
+		writeJSON(w, http.StatusOK, slashText("Working on it..."))
+
+		go func() {
+			err := server.parser.SendResponse(responseURL, <-done)
+			if err != nil {
+				misc.Logger.Warnf("Failed to send delayed response to Slack slash command: [%v]", err)
+			}
+		}()
+	}
+}
+
+// runSlashCommand executes the action named by the first argument, returning a payload containing the result or the encountered error.
+// Restricted actions are only executed if the requesting user or channel is allowed to use them
+func (server *Server) runSlashCommand(args []string, allowed bool) *models.SlackPayload {
+	if len(args) == 0 {
+		args = []string{"help"}
+	}
+
+	for _, command := range slashCommands {
+		if !strings.EqualFold(command.Name, args[0]) {
+			continue
+		}
+
+		if command.Restricted && !allowed {
+			return slashText(fmt.Sprintf("You are not allowed to use `%s`", command.Name))
+		}
+
+		payload, err := command.Run(server, args[1:])
+		if err != nil {
+			return slashText(fmt.Sprintf("Error: %v\nUsage: `%s %s`", err, command.Name, command.Usage))
+		}
+
+		return payload
+	}
+
+	return slashText(fmt.Sprintf("Unknown command %q, use `help` to list all available commands", args[0]))
+}
+
+// runSlashStats shows the kill and loss statistics of the matching corporations
+func runSlashStats(server *Server, args []string) (*models.SlackPayload, error) {
+	days, args := parseSlashPeriod(args)
+
+	corporations, err := server.findCorporations(strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -days)
+
+	payload := slashText(fmt.Sprintf("Statistics for the last %d days", days))
+
+	for _, corporation := range corporations {
+		killmails, err := server.database.LoadKillmails(corporation.ID, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		digest := reports.GenerateDigest(corporation, killmails, from, to, 0, server.parser.Prices())
+
+		var stats models.SlackAttachment
+
+		stats.Title = parser.CorporationName(corporation)
+		stats.Fallback = fmt.Sprintf("%s: %d kills, %d losses", stats.Title, digest.Kills, digest.Losses)
+
+		if digest.ISKDestroyed >= digest.ISKLost {
+			stats.Color = "good"
+		} else {
+			stats.Color = "danger"
+		}
+
+		stats.Fields = append(stats.Fields, models.SlackField{
+			Title: "Kills / losses",
+			Value: fmt.Sprintf("%s / %s", humanize.Comma(int64(digest.Kills)), humanize.Comma(int64(digest.Losses))),
+			Short: true,
+		})
+
+		stats.Fields = append(stats.Fields, models.SlackField{
+			Title: "Efficiency",
+			Value: fmt.Sprintf("%.1f%%", digest.Efficiency()),
+			Short: true,
+		})
+
+		stats.Fields = append(stats.Fields, models.SlackField{
+			Title: "ISK destroyed",
+			Value: fmt.Sprintf("%s ISK", humanize.Commaf(digest.ISKDestroyed)),
+			Short: true,
+		})
+
+		stats.Fields = append(stats.Fields, models.SlackField{
+			Title: "ISK lost",
+			Value: fmt.Sprintf("%s ISK", humanize.Commaf(digest.ISKLost)),
+			Short: true,
+		})
+
+		payload.Attachments = append(payload.Attachments, stats)
+	}
+
+	return payload, nil
+}
+
+// runSlashTop shows the top pilots of the matching corporations
+func runSlashTop(server *Server, args []string) (*models.SlackPayload, error) {
+	days, args := parseSlashPeriod(args)
+
+	corporations, err := server.findCorporations(strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -days)
+
+	payload := slashText(fmt.Sprintf("Top pilots of the last %d days", days))
+
+	for _, corporation := range corporations {
+		killmails, err := server.database.LoadKillmails(corporation.ID, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		leaderboard := reports.GenerateLeaderboard(corporation, killmails, from, to, slashRankingSize, server.parser.Prices())
+		server.parser.FillPilotNames(leaderboard.KillsParticipated)
+		server.parser.FillPilotNames(leaderboard.ISKDestroyed)
+
+		var top models.SlackAttachment
+
+		top.Title = parser.CorporationName(corporation)
+		top.Fallback = fmt.Sprintf("Top pilots of %s", top.Title)
+		top.Color = "good"
+
+		top.Fields = append(top.Fields, models.SlackField{
+			Title: "Kills participated",
+			Value: formatRanking(leaderboard.KillsParticipated, func(value float64) string {
+				return humanize.Comma(int64(value))
+			}),
+			Short: true,
+		})
+
+		top.Fields = append(top.Fields, models.SlackField{
+			Title: "ISK destroyed",
+			Value: formatRanking(leaderboard.ISKDestroyed, func(value float64) string {
+				return fmt.Sprintf("%s ISK", humanize.Comma(int64(value)))
+			}),
+			Short: true,
+		})
+
+		payload.Attachments = append(payload.Attachments, top)
+	}
+
+	return payload, nil
+}
+
+// runSlashPilot shows the statistics of the given pilot within the killmail history of all tracked corporations
+func runSlashPilot(server *Server, args []string) (*models.SlackPayload, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("Missing pilot name")
+	}
+
+	characterID, characterName, err := server.parser.ResolveCharacterID(strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	corporations, err := server.database.LoadAllCorporations()
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -slashHistoryDays)
+	prices := server.parser.Prices()
+	seen := make(map[int64]bool)
+
+	var kills, finalBlows, losses int
+	var damageDone int64
+	var iskDestroyed, iskLost float64
+
+	for _, corporation := range corporations {
+		killmails, err := server.database.LoadKillmails(corporation.ID, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, killmail := range killmails {
+			if seen[killmail.KillID] {
+				continue
+			}
+
+			if killmail.Entry.Victim.CharacterID == characterID {
+				seen[killmail.KillID] = true
+				losses++
+				iskLost += killmail.Entry.Value(prices)
+				continue
+			}
+
+			for _, attacker := range killmail.Entry.Attackers {
+				if attacker.CharacterID != characterID {
+					continue
+				}
+
+				seen[killmail.KillID] = true
+				kills++
+				damageDone += attacker.DamageDone
+				iskDestroyed += killmail.Entry.Value(prices)
+
+				if attacker.FinalBlow == 1 {
+					finalBlows++
+				}
+
+				break
+			}
+		}
+	}
+
+	payload := slashText(fmt.Sprintf("Statistics of the last %d days", slashHistoryDays))
+
+	var pilot models.SlackAttachment
+
+	pilot.Title = characterName
+	pilot.TitleLink = fmt.Sprintf("https://zkillboard.com/character/%d/", characterID)
+	pilot.Fallback = fmt.Sprintf("%s: %d kills, %d losses", characterName, kills, losses)
+	pilot.ThumbURL = fmt.Sprintf("https://imageserver.eveonline.com/Character/%d_64.jpg", characterID)
+
+	pilot.Fields = append(pilot.Fields, models.SlackField{
+		Title: "Kills / losses",
+		Value: fmt.Sprintf("%s / %s", humanize.Comma(int64(kills)), humanize.Comma(int64(losses))),
+		Short: true,
+	})
+
+	pilot.Fields = append(pilot.Fields, models.SlackField{
+		Title: "Final blows",
+		Value: humanize.Comma(int64(finalBlows)),
+		Short: true,
+	})
+
+	pilot.Fields = append(pilot.Fields, models.SlackField{
+		Title: "ISK destroyed",
+		Value: fmt.Sprintf("%s ISK", humanize.Commaf(iskDestroyed)),
+		Short: true,
+	})
+
+	pilot.Fields = append(pilot.Fields, models.SlackField{
+		Title: "ISK lost",
+		Value: fmt.Sprintf("%s ISK", humanize.Commaf(iskLost)),
+		Short: true,
+	})
+
+	pilot.Fields = append(pilot.Fields, models.SlackField{
+		Title: "Damage done",
+		Value: humanize.Comma(damageDone),
+		Short: true,
+	})
+
+	payload.Attachments = append(payload.Attachments, pilot)
+
+	return payload, nil
+}
+
+// runSlashIgnore adds the given solar system to the ignore list of the matching corporation.
+// Solar system names may consist of multiple words, either quoted or matched as the longest prefix of the arguments naming a solar system
+func runSlashIgnore(server *Server, args []string) (*models.SlackPayload, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("Missing solar system")
+	}
+
+	solarSystemID, solarSystemName, args, err := server.resolveSlashSolarSystem(args)
+	if err != nil {
+		return nil, err
+	}
+
+	corporation, err := server.findCorporation(strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.database.AddIgnoredSolarSystem(corporation.ID, solarSystemID)
+	if err != nil {
+		return nil, err
+	}
+
+	misc.Logger.Infof("Ignoring solar system #%d for corporation #%d via Slack", solarSystemID, corporation.EVECorporationID)
+
+	server.parser.TriggerUpdate()
+
+	return slashText(fmt.Sprintf("Kills and losses of %s in %s will be ignored from now on", parser.CorporationName(corporation), solarSystemName)), nil
+}
+
+// runSlashLast lists the latest kills and losses of the matching corporations
+func runSlashLast(server *Server, args []string) (*models.SlackPayload, error) {
+	corporations, err := server.findCorporations(strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -slashHistoryDays)
+	prices := server.parser.Prices()
+
+	latest := make([]*models.Killmail, 0)
+
+	for _, corporation := range corporations {
+		killmails, err := server.database.LoadKillmails(corporation.ID, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		latest = append(latest, killmails...)
+	}
+
+	sort.Sort(sort.Reverse(byKillTime(latest)))
+
+	if len(latest) > slashRankingSize {
+		latest = latest[:slashRankingSize]
+	}
+
+	if len(latest) == 0 {
+		return slashText(fmt.Sprintf("No kills or losses recorded within the last %d days", slashHistoryDays)), nil
+	}
+
+	lines := make([]string, 0, len(latest))
+
+	for _, killmail := range latest {
+		server.parser.FillNames(&killmail.Entry)
+
+		lines = append(lines, fmt.Sprintf("%s %s: <https://zkillboard.com/kill/%d/|%s> of %s (%s ISK)", killmail.KillTime.UTC().Format(models.ZKillboardTimeFormat), killmail.Type, killmail.KillID, server.parser.ItemTypeName(killmail.Entry.Victim.ShipTypeID), killmail.Entry.Victim.CharacterName, humanize.Comma(int64(killmail.Entry.Value(prices)))))
+	}
+
+	return slashText(strings.Join(lines, "\n")), nil
+}
+
+// runSlashHelp lists all available slash command actions
+func runSlashHelp(server *Server, args []string) (*models.SlackPayload, error) {
+	lines := make([]string, 0, len(slashCommands))
+
+	for _, command := range slashCommands {
+		lines = append(lines, strings.TrimSpace(fmt.Sprintf("`%s %s` - %s", command.Name, command.Usage, command.Description)))
+	}
+
+	return slashText(strings.Join(lines, "\n")), nil
+}
+
+// resolveSlashSolarSystem resolves the solar system named at the start of the given arguments, returning its ID and name along with the remaining arguments.
+// A name enclosed in quotes is used as is, otherwise the longest prefix of the arguments matching a solar system is used
+func (server *Server) resolveSlashSolarSystem(args []string) (int64, string, []string, error) {
+	if strings.HasPrefix(args[0], "\"") {
+		for i := range args {
+			if (i > 0 || len(args[0]) > 1) && strings.HasSuffix(args[i], "\"") {
+				name := strings.Trim(strings.Join(args[:i+1], " "), "\"")
+
+				solarSystemID, solarSystemName, err := server.parser.ResolveSolarSystemID(name)
+				if err != nil {
+					return 0, "", nil, err
+				}
+
+				return solarSystemID, solarSystemName, args[i+1:], nil
+			}
+		}
+
+		return 0, "", nil, fmt.Errorf("Missing closing quote for solar system")
+	}
+
+	var err error
+
+	for i := len(args); i > 0; i-- {
+		var solarSystemID int64
+		var solarSystemName string
+
+		solarSystemID, solarSystemName, err = server.parser.ResolveSolarSystemID(strings.Join(args[:i], " "))
+		if err == nil {
+			return solarSystemID, solarSystemName, args[i:], nil
+		}
+	}
+
+	return 0, "", nil, err
+}
+
+// slackAllowed checks whether the given Slack user or channel ID is allowed to use restricted actions. Names are not accepted as they can be changed by users. If no allow list was configured, nobody is allowed
+func (server *Server) slackAllowed(userID string, channelID string) bool {
+	for _, allowed := range server.config.SlackIgnoreAllowList {
+		allowed = strings.TrimSpace(allowed)
+		if len(allowed) == 0 {
+			continue
+		}
+
+		if allowed == userID || allowed == channelID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findCorporations returns all tracked corporations matching the given EVE corporation ID or (partial) name, returning all corporations if no filter was given
+func (server *Server) findCorporations(filter string) ([]*models.Corporation, error) {
+	corporations, err := server.database.LoadAllCorporations()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filter) == 0 {
+		return corporations, nil
+	}
+
+	matches := make([]*models.Corporation, 0)
+	eveCorporationID, _ := strconv.ParseInt(filter, 10, 64)
+
+	for _, corporation := range corporations {
+		if corporation.EVECorporationID == eveCorporationID || strings.Contains(strings.ToLower(corporation.Name), strings.ToLower(filter)) {
+			matches = append(matches, corporation)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("No tracked corporation matching %q found", filter)
+	}
+
+	return matches, nil
+}
+
+// findCorporation returns the single tracked corporation matching the given filter, returning an error if none or multiple corporations match
+func (server *Server) findCorporation(filter string) (*models.Corporation, error) {
+	corporations, err := server.findCorporations(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(corporations) != 1 {
+		return nil, fmt.Errorf("%d corporations are tracked, please specify one", len(corporations))
+	}
+
+	return corporations[0], nil
+}
+
+// parseSlashPeriod parses the period given as last argument, returning the number of days (capped at one year) along with the remaining arguments
+func parseSlashPeriod(args []string) (int, []string) {
+	if len(args) == 0 {
+		return slashDefaultDays, args
+	}
+
+	last := strings.ToLower(args[len(args)-1])
+
+	switch last {
+	case "day":
+		return 1, args[:len(args)-1]
+	case "week":
+		return 7, args[:len(args)-1]
+	case "month":
+		return 30, args[:len(args)-1]
+	}
+
+	if strings.HasSuffix(last, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(last, "d"))
+		if err == nil && days > 0 {
+			if days > slashMaxDays {
+				days = slashMaxDays
+			}
+
+			return days, args[:len(args)-1]
+		}
+	}
+
+	return slashDefaultDays, args
+}
+
+// formatRanking formats the given pilot ranking as numbered list, formatting values using the provided function
+func formatRanking(ranking []reports.PilotStatistic, format func(value float64) string) string {
+	if len(ranking) == 0 {
+		return "-"
+	}
+
+	lines := make([]string, 0, len(ranking))
+
+	for i, pilot := range ranking {
+		lines = append(lines, fmt.Sprintf("%d. <https://zkillboard.com/character/%d/|%s> (%s)", i+1, pilot.CharacterID, pilot.CharacterName, format(pilot.Value)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// slashText creates an ephemeral response payload containing the given text
+func slashText(text string) *models.SlackPayload {
+	payload := &models.SlackPayload{
+		ResponseType: "ephemeral",
+		Text:         text,
+	}
+
+	return payload
+}
+
+// byKillTime represents an array of killmails, used for sorting by kill time
+type byKillTime []*models.Killmail
+
+// Len returns the length of the array of killmails to sort
+func (k byKillTime) Len() int {
+	return len(k)
+}
+
+// Swap swaps two entries in the array of killmails to sort
+func (k byKillTime) Swap(i, j int) {
+	k[i], k[j] = k[j], k[i]
+}
+
+// Less is used for sorting the array of killmails by kill time
+func (k byKillTime) Less(i, j int) bool {
+	return k[i].KillTime.Before(k[j].KillTime)
+}
@@ -0,0 +1,102 @@
+package server
+
+// layoutTemplate contains the header and footer shared by all dashboard pages
+const layoutTemplate = `{{define "header"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} - eveslackkills</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.3em 0.8em; text-align: left; border-bottom: 1px solid #ddd; }
+td.number { text-align: right; }
+.kill { color: #2a7a2a; }
+.loss { color: #b22222; }
+.bar { display: inline-block; height: 0.8em; }
+.bar.kill { background: #2a7a2a; }
+.bar.loss { background: #b22222; }
+.error { color: #b22222; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p><a href="/">Overview</a> | Generated {{evetime .Generated}}</p>
+{{end}}
+{{define "footer"}}</body>
+</html>
+{{end}}
+{{define "status"}}<h2>Health</h2>
+<table>
+<tr><th>Running since</th><td>{{evetime .StartedAt}}</td></tr>
+<tr><th>Last update</th><td>{{if .LastUpdate.IsZero}}never{{else}}{{evetime .LastUpdate}}{{end}}</td></tr>
+<tr><th>Tracked corporations</th><td>{{.Corporations}}</td></tr>
+<tr><th>Messages waiting for retry</th><td>{{.RetryQueueSize}}</td></tr>
+<tr><th>Last error</th><td>{{if .LastError}}<span class="error">{{.LastError}}</span> ({{evetime .LastErrorAt}}){{else}}none{{end}}</td></tr>
+</table>
+{{end}}`
+
+// indexTemplate renders the overview of all tracked corporations
+const indexTemplate = `{{template "header" .}}
+<h2>Corporations (last 7 days)</h2>
+<table>
+<tr><th>Corporation</th><th>Kills</th><th>Losses</th><th>ISK destroyed</th><th>ISK lost</th><th>Efficiency</th></tr>
+{{range .Corporations}}<tr>
+<td><a href="/corporation/{{.Corporation.EVECorporationID}}">{{.Name}}</a></td>
+<td class="number">{{.Digest.Kills}}</td>
+<td class="number">{{.Digest.Losses}}</td>
+<td class="number kill">{{isk .Digest.ISKDestroyed}}</td>
+<td class="number loss">{{isk .Digest.ISKLost}}</td>
+<td class="number">{{printf "%.1f%%" .Digest.Efficiency}}</td>
+</tr>{{end}}
+</table>
+<h2>Recent posts</h2>
+<table>
+<tr><th>Posted</th><th>Corporation</th><th>Type</th><th>Title</th></tr>
+{{range .RecentPosts}}<tr>
+<td>{{evetime .PostedAt}}</td>
+<td>{{.CorporationID}}</td>
+<td class="{{.Type}}">{{.Type}}</td>
+<td>{{if .KillID}}<a href="https://zkillboard.com/kill/{{.KillID}}/">{{.Title}}</a>{{else}}{{.Title}}{{end}}</td>
+</tr>{{else}}<tr><td colspan="4">No messages posted yet</td></tr>{{end}}
+</table>
+{{template "status" .Status}}
+{{template "footer" .}}`
+
+// corporationTemplate renders the recent activity and statistics of a single corporation
+const corporationTemplate = `{{template "header" .}}
+<h2>ISK destroyed vs. lost per week</h2>
+<table>
+<tr><th>Week</th><th>Kills</th><th>Losses</th><th>ISK destroyed / lost</th></tr>
+{{range .Weeks}}<tr>
+<td>{{.Start.Format "2006-01-02"}}</td>
+<td class="number">{{.Kills}}</td>
+<td class="number">{{.Losses}}</td>
+<td>
+<span class="bar kill" style="width: {{printf "%.0f" .DestroyedWidth}}px"></span> {{isk .ISKDestroyed}}<br>
+<span class="bar loss" style="width: {{printf "%.0f" .LostWidth}}px"></span> {{isk .ISKLost}}
+</td>
+</tr>{{end}}
+</table>
+<h2>Top pilots (last 30 days)</h2>
+<table>
+<tr><th>Kills participated</th><th></th><th>ISK destroyed</th><th></th></tr>
+{{range .TopPilots}}<tr>
+<td>{{with .Kills}}<a href="https://zkillboard.com/character/{{.CharacterID}}/">{{.CharacterName}}</a>{{end}}</td>
+<td class="number">{{with .Kills}}{{printf "%.0f" .Value}}{{end}}</td>
+<td>{{with .ISK}}<a href="https://zkillboard.com/character/{{.CharacterID}}/">{{.CharacterName}}</a>{{end}}</td>
+<td class="number">{{with .ISK}}{{isk .Value}}{{end}}</td>
+</tr>{{else}}<tr><td colspan="4">No kills recorded</td></tr>{{end}}
+</table>
+<h2>Recent kills and losses</h2>
+<table>
+<tr><th>Time</th><th>Type</th><th>Victim</th><th>Ship</th><th>Value</th></tr>
+{{range .Recent}}<tr>
+<td>{{evetime .KillTime}}</td>
+<td class="{{.Type}}">{{.Type}}</td>
+<td>{{.Victim}}</td>
+<td><a href="https://zkillboard.com/kill/{{.KillID}}/">{{.ShipName}}</a></td>
+<td class="number">{{isk .Value}}</td>
+</tr>{{else}}<tr><td colspan="5">No kills or losses recorded</td></tr>{{end}}
+</table>
+{{template "footer" .}}`
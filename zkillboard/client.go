@@ -0,0 +1,307 @@
+package zkillboard
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/morpheusxaut/eveslackkills/metrics"
+	"github.com/morpheusxaut/eveslackkills/misc"
+	"github.com/morpheusxaut/eveslackkills/models"
+)
+
+const (
+	// DefaultRoot represents the root URL of the zKillboard API
+	DefaultRoot = "https://zkillboard.com/api"
+	// DefaultUserAgent represents the User-Agent sent to zKillboard if none was configured
+	DefaultUserAgent = "eveslackkills github.com/morpheusxaut/eveslackkills"
+	// DefaultTimeout represents the timeout of a single request if none was configured
+	DefaultTimeout = time.Second * 30
+	// DefaultRequestsPerSecond represents the number of requests per second allowed if no rate limit was configured
+	DefaultRequestsPerSecond = 1.0
+	// timeFormat represents the format of start and end times used by the zKillboard API
+	timeFormat = "200601021504"
+	// MaxPages represents the maximum number of pages retrieved per update while catching up to a checkpoint if the backlog is posted individually
+	MaxPages = 50
+	// maxAttempts represents the number of attempts made for a request before giving up
+	maxAttempts = 3
+	// defaultRetryAfter represents the delay before retrying a request if the server did not provide a Retry-After header
+	defaultRetryAfter = time.Second * 10
+)
+
+// Client is used for retrieving kills and losses from the zKillboard API, safe for concurrent use
+type Client struct {
+	mutex       sync.RWMutex
+	root        string
+	userAgent   string
+	client      *http.Client
+	limiter     *Limiter
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// NewClient creates a new Client with the given root URL, User-Agent and request timeout, rate limited by the given limiter
+func NewClient(root string, userAgent string, timeout time.Duration, limiter *Limiter) *Client {
+	c := &Client{
+		root:      strings.TrimSuffix(root, "/"),
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: timeout},
+		limiter:   limiter,
+	}
+
+	return c
+}
+
+// SetupClient creates a new Client using the zKillboard settings of the given configuration, falling back to defaults for unset values
+func SetupClient(conf *misc.Configuration) *Client {
+	userAgent := conf.ZKillboardUserAgent
+	if len(userAgent) == 0 {
+		userAgent = DefaultUserAgent
+	}
+
+	timeout := time.Second * time.Duration(conf.ZKillboardTimeout)
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	rate := conf.ZKillboardRequestsPerSecond
+	if rate == 0 {
+		rate = DefaultRequestsPerSecond
+	}
+
+	return NewClient(DefaultRoot, userAgent, timeout, NewLimiter(rate, conf.ZKillboardBurst))
+}
+
+// FetchKills retrieves all kills of the given corporation with a kill ID greater than the provided one, oldest first, walking at most the given number of pages (0 for no limit).
+// The returned flag reports whether the page limit was reached before all kills had been retrieved, in which case the oldest kills following the given kill ID are returned
+func (c *Client) FetchKills(corporationID int64, afterKillID int64, pageLimit int) ([]models.ZKillboardEntry, bool, error) {
+	return c.FetchPages(fmt.Sprintf("%s/kills/corporationID/%d/afterKillID/%d/orderDirection/asc", c.root, corporationID, afterKillID), afterKillID, pageLimit)
+}
+
+// FetchLosses retrieves all losses of the given corporation with a kill ID greater than the provided one, oldest first, walking at most the given number of pages (0 for no limit).
+// The returned flag reports whether the page limit was reached before all losses had been retrieved, in which case the oldest losses following the given kill ID are returned
+func (c *Client) FetchLosses(corporationID int64, afterKillID int64, pageLimit int) ([]models.ZKillboardEntry, bool, error) {
+	return c.FetchPages(fmt.Sprintf("%s/losses/corporationID/%d/afterKillID/%d/orderDirection/asc", c.root, corporationID, afterKillID), afterKillID, pageLimit)
+}
+
+// FetchKillsBetween retrieves all kills of the given corporation within the given period, walking every page of results
+func (c *Client) FetchKillsBetween(corporationID int64, from time.Time, to time.Time) ([]models.ZKillboardEntry, error) {
+	entries, _, err := c.FetchPages(fmt.Sprintf("%s/kills/corporationID/%d/startTime/%s/endTime/%s", c.root, corporationID, from.UTC().Format(timeFormat), to.UTC().Format(timeFormat)), 0, 0)
+	return entries, err
+}
+
+// FetchLossesBetween retrieves all losses of the given corporation within the given period, walking every page of results
+func (c *Client) FetchLossesBetween(corporationID int64, from time.Time, to time.Time) ([]models.ZKillboardEntry, error) {
+	entries, _, err := c.FetchPages(fmt.Sprintf("%s/losses/corporationID/%d/startTime/%s/endTime/%s", c.root, corporationID, from.UTC().Format(timeFormat), to.UTC().Format(timeFormat)), 0, 0)
+	return entries, err
+}
+
+// FetchLatestKillID retrieves the ID of the newest kill of the given corporation, returning 0 if the corporation has no kills yet
+func (c *Client) FetchLatestKillID(corporationID int64) (int64, error) {
+	return c.fetchLatestID(fmt.Sprintf("%s/kills/corporationID/%d", c.root, corporationID))
+}
+
+// FetchLatestLossID retrieves the ID of the newest loss of the given corporation, returning 0 if the corporation has no losses yet
+func (c *Client) FetchLatestLossID(corporationID int64) (int64, error) {
+	return c.fetchLatestID(fmt.Sprintf("%s/losses/corporationID/%d", c.root, corporationID))
+}
+
+// fetchLatestID retrieves the first page of the given zKillboard API URL and returns the highest kill ID listed
+func (c *Client) fetchLatestID(url string) (int64, error) {
+	result, err := c.FetchEntries(fmt.Sprintf("%s/page/1/", url))
+	if err != nil {
+		return 0, err
+	}
+
+	var latestID int64
+	for _, entry := range result {
+		if entry.KillID > latestID {
+			latestID = entry.KillID
+		}
+	}
+
+	return latestID, nil
+}
+
+// FetchPages retrieves the pages of the given zKillboard API URL until an empty page or a page without entries newer than the given kill ID is returned.
+// At most the given number of pages (0 for no limit) are retrieved, returning whether entries on later pages were left out because the limit was reached.
+// Entries appearing on multiple pages (caused by new kills shifting the results) are only returned once
+func (c *Client) FetchPages(url string, afterKillID int64, pageLimit int) ([]models.ZKillboardEntry, bool, error) {
+	entries := make([]models.ZKillboardEntry, 0)
+	seen := make(map[int64]bool)
+
+	for page := 1; pageLimit <= 0 || page <= pageLimit; page++ {
+		result, err := c.FetchEntries(fmt.Sprintf("%s/page/%d/", url, page))
+		if err != nil {
+			return nil, false, err
+		}
+
+		added := 0
+
+		for _, entry := range result {
+			if entry.KillID <= afterKillID || seen[entry.KillID] {
+				continue
+			}
+
+			seen[entry.KillID] = true
+			entries = append(entries, entry)
+			added++
+		}
+
+		misc.Logger.WithField("url", url).Tracef("Fetched page %d of %q with %d new entries", page, url, added)
+
+		if added == 0 {
+			return entries, false, nil
+		}
+	}
+
+	misc.Logger.WithField("url", url).Infof("Stopped fetching %q after %d pages, remaining entries have not been retrieved", url, pageLimit)
+
+	return entries, true, nil
+}
+
+// FetchEntries retrieves the given zKillboard API URL and parses the returned entries
+func (c *Client) FetchEntries(url string) ([]models.ZKillboardEntry, error) {
+	response, err := c.request(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.ZKillboardEntry
+
+	err = json.Unmarshal(response, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse zKillboard response: %v", err)
+	}
+
+	return entries, nil
+}
+
+// request performs a rate limited request to the given URL, retrying if the server is busy or asked to slow down, and returns the read data
+func (c *Client) request(url string) ([]byte, error) {
+	var err error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var response []byte
+		var retryAfter time.Duration
+
+		response, retryAfter, err = c.attempt(url)
+		if err == nil {
+			return response, nil
+		}
+
+		if retryAfter <= 0 {
+			c.recordError(err)
+			return nil, err
+		}
+
+		misc.Logger.WithField("url", url).Warnf("Request to %q failed (attempt %d of %d), retrying in %v: [%v]", url, attempt, maxAttempts, retryAfter, err)
+
+		c.limiter.Pause(retryAfter)
+	}
+
+	c.recordError(err)
+
+	return nil, err
+}
+
+// LastError returns the most recent error encountered while requesting zKillboard along with the time it occurred, an empty string if no request failed yet
+func (c *Client) LastError() (string, time.Time) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.lastError, c.lastErrorAt
+}
+
+// recordError stores the given error as the most recent error encountered while requesting zKillboard
+func (c *Client) recordError(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lastError = err.Error()
+	c.lastErrorAt = time.Now()
+}
+
+// attempt performs a single request to the given URL, returning the read data or an error along with the delay before the request may be retried (zero if it should not be retried)
+func (c *Client) attempt(url string) ([]byte, time.Duration, error) {
+	c.limiter.Wait()
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	misc.Logger.WithField("url", url).Tracef("Requesting zKillboard URL %q", url)
+
+	start := time.Now()
+
+	resp, err := c.client.Do(req)
+	metrics.ZKillboardRequestDuration.ObserveSince(start)
+	if err != nil {
+		metrics.ZKillboardRequests.Inc("error")
+		return nil, defaultRetryAfter, err
+	}
+
+	defer resp.Body.Close()
+
+	metrics.ZKillboardRequests.Inc(strconv.Itoa(resp.StatusCode))
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		break
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return nil, parseRetryAfter(resp.Header), fmt.Errorf("Received HTTP status code %s (%d)", resp.Status, resp.StatusCode)
+	default:
+		return nil, 0, fmt.Errorf("Received non-OK HTTP status code %s (%d)", resp.Status, resp.StatusCode)
+	}
+
+	var body io.Reader = resp.Body
+
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer reader.Close()
+
+		body = reader
+	}
+
+	response, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, defaultRetryAfter, err
+	}
+
+	return response, 0, nil
+}
+
+// parseRetryAfter parses the Retry-After header given either in seconds or as a HTTP date, returning a default delay if the header is missing or invalid
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil && seconds > 0 {
+		return time.Second * time.Duration(seconds)
+	}
+
+	date, err := http.ParseTime(value)
+	if err == nil {
+		delay := date.Sub(time.Now())
+		if delay > 0 {
+			return delay
+		}
+	}
+
+	return defaultRetryAfter
+}
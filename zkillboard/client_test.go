@@ -0,0 +1,36 @@
+package zkillboard
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{name: "missing header", value: "", min: defaultRetryAfter, max: defaultRetryAfter},
+		{name: "seconds", value: "30", min: time.Second * 30, max: time.Second * 30},
+		{name: "zero seconds", value: "0", min: defaultRetryAfter, max: defaultRetryAfter},
+		{name: "negative seconds", value: "-5", min: defaultRetryAfter, max: defaultRetryAfter},
+		{name: "invalid value", value: "soon", min: defaultRetryAfter, max: defaultRetryAfter},
+		{name: "future date", value: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), min: time.Second * 55, max: time.Minute},
+		{name: "past date", value: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), min: defaultRetryAfter, max: defaultRetryAfter},
+	}
+
+	for _, test := range tests {
+		header := http.Header{}
+		if len(test.value) > 0 {
+			header.Set("Retry-After", test.value)
+		}
+
+		delay := parseRetryAfter(header)
+		if delay < test.min || delay > test.max {
+			t.Errorf("%s: expected delay between %v and %v, got %v", test.name, test.min, test.max, delay)
+		}
+	}
+}
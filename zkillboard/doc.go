@@ -0,0 +1,3 @@
+// Package zkillboard provides a client for the zKillboard API, identifying itself with a User-Agent, handling errors and abiding to the API's rate limits.
+// A single rate limiter is shared by all requests of a client, regardless of the corporation being queried.
+package zkillboard
@@ -0,0 +1,84 @@
+package zkillboard
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket rate limiter allowing a steady rate of requests with occasional bursts, safe for concurrent use
+type Limiter struct {
+	mutex        sync.Mutex
+	rate         float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// NewLimiter creates a new Limiter allowing rate requests per second with bursts of up to burst requests. A non-positive rate disables the limit
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	l := &Limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+
+	return l
+}
+
+// Wait blocks until a request is allowed by the limiter, consuming a token
+func (l *Limiter) Wait() {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return
+		}
+
+		time.Sleep(delay)
+	}
+}
+
+// Pause blocks all requests for at least the given duration, used to respect the Retry-After header sent by the server
+func (l *Limiter) Pause(d time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	until := time.Now().Add(d)
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
+
+// reserve tries to consume a token, returning the time to wait before trying again or zero if a token was consumed
+func (l *Limiter) reserve() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
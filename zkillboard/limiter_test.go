@@ -0,0 +1,57 @@
+package zkillboard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterReserve(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		rate         float64
+		burst        float64
+		tokens       float64
+		last         time.Time
+		blockedUntil time.Time
+		min          time.Duration
+		max          time.Duration
+		tokensLeft   float64
+	}{
+		{name: "token available", rate: 1, burst: 1, tokens: 1, last: now, min: 0, max: 0, tokensLeft: 0},
+		{name: "burst available", rate: 1, burst: 3, tokens: 3, last: now, min: 0, max: 0, tokensLeft: 2},
+		{name: "no token left", rate: 1, burst: 1, tokens: 0, last: now, min: time.Millisecond * 900, max: time.Second, tokensLeft: 0},
+		{name: "half token left", rate: 2, burst: 1, tokens: 0.5, last: now, min: time.Millisecond * 200, max: time.Millisecond * 250, tokensLeft: 0.5},
+		{name: "refilled after idling", rate: 1, burst: 2, tokens: 0, last: now.Add(-time.Minute), min: 0, max: 0, tokensLeft: 1},
+		{name: "paused", rate: 1, burst: 1, tokens: 1, last: now, blockedUntil: now.Add(time.Second * 10), min: time.Second * 9, max: time.Second * 10, tokensLeft: 1},
+		{name: "unlimited", rate: 0, burst: 1, tokens: 0, last: now, min: 0, max: 0, tokensLeft: 0},
+	}
+
+	for _, test := range tests {
+		l := &Limiter{
+			rate:         test.rate,
+			burst:        test.burst,
+			tokens:       test.tokens,
+			last:         test.last,
+			blockedUntil: test.blockedUntil,
+		}
+
+		delay := l.reserve()
+		if delay < test.min || delay > test.max {
+			t.Errorf("%s: expected delay between %v and %v, got %v", test.name, test.min, test.max, delay)
+		}
+
+		if l.tokens < test.tokensLeft-0.01 || l.tokens > test.tokensLeft+0.01 {
+			t.Errorf("%s: expected %.2f tokens left, got %.2f", test.name, test.tokensLeft, l.tokens)
+		}
+	}
+}
+
+func TestNewLimiterMinimumBurst(t *testing.T) {
+	l := NewLimiter(1, 0)
+
+	if l.burst != 1 || l.tokens != 1 {
+		t.Errorf("Expected burst and tokens of 1, got %.0f and %.0f", l.burst, l.tokens)
+	}
+}